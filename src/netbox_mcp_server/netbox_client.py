@@ -6,9 +6,65 @@ This module provides a base class for NetBox client implementations and a REST A
 """
 
 import abc
+import copy
+import json
+import logging
+import math
+import random
+import re
+import threading
+import time
+from collections import OrderedDict, deque
+from email.utils import parsedate_to_datetime
 from typing import Any
 
 import httpx
+from opentelemetry import trace
+
+# Spans are no-ops unless an OpenTelemetry SDK is configured (e.g. opentelemetry-instrument)
+tracer = trace.get_tracer(__name__)
+
+# Responses worth retrying: rate limiting and transient gateway/availability errors
+RETRYABLE_STATUS_CODES = frozenset({429, 502, 503, 504})
+
+# Upper bound on any single wait, so a large Retry-After cannot stall a tool call
+MAX_RETRY_DELAY = 60.0
+
+# How many recent NetBox round trips are kept for request_stats()
+RECENT_REQUEST_WINDOW = 1000
+
+
+def _parse_retry_after(value: str | None) -> float | None:
+    """Convert a Retry-After header (delta-seconds or HTTP-date) to seconds from now."""
+    if not value:
+        return None
+    try:
+        return float(value)
+    except ValueError:
+        pass
+    try:
+        return parsedate_to_datetime(value).timestamp() - time.time()
+    except (TypeError, ValueError):
+        return None
+
+
+def _route_template(endpoint: str) -> str:
+    """
+    Replace object IDs in an endpoint with {id}, e.g. "dcim/interfaces/{id}/trace".
+
+    Span names must have low cardinality, so they use this rather than the raw path.
+    """
+    return re.sub(r"(?<=/)\d+(?=/|$)", "{id}", endpoint.strip("/"))
+
+
+def _is_integration_error(status: int | None) -> bool:
+    """
+    Whether a round trip outcome means the integration is degraded.
+
+    Transport failures (None), auth failures (401/403), rate limiting (429) and server
+    errors (5xx) count; other 4xx responses are answers to bad queries, not outages.
+    """
+    return status is None or status in (401, 403, 429) or status >= 500
 
 
 class NetBoxClientBase(abc.ABC):
@@ -19,6 +75,10 @@ class NetBoxClientBase(abc.ABC):
     either via the REST API or directly via the ORM in a NetBox plugin.
     """
 
+    # Read retry policy, reported by the server health tool; clients that never retry keep 0
+    max_retries: int = 0
+    retry_backoff: float = 0.0
+
     @abc.abstractmethod
     def get(
         self,
@@ -26,6 +86,7 @@ class NetBoxClientBase(abc.ABC):
         id: int | None = None,
         params: dict[str, Any] | None = None,
         fallback_endpoint: str | None = None,
+        use_cache: bool = True,
     ) -> dict[str, Any] | list[dict[str, Any]]:
         """
         Retrieve one or more objects from NetBox.
@@ -36,6 +97,7 @@ class NetBoxClientBase(abc.ABC):
             params: Optional query parameters for filtering
             fallback_endpoint: Optional alternative endpoint to try if primary returns 404
                                (used for NetBox version compatibility)
+            use_cache: Set to False to bypass any response cache the client keeps
 
         Returns:
             For single object queries (with id): Returns the object dict
@@ -132,6 +194,20 @@ class NetBoxClientBase(abc.ABC):
         """
         pass
 
+    @abc.abstractmethod
+    def request_stats(self, window: float = 300) -> dict[str, Any]:
+        """
+        Summarize recent calls to NetBox in a time window.
+
+        Args:
+            window: How many seconds back to look
+
+        Returns:
+            Dict with requests, errors, error_rate, and latency_ms percentiles
+            (p50/p95/p99, None when there were no requests)
+        """
+        pass
+
 
 class NetBoxRestClient(NetBoxClientBase):
     """
@@ -161,7 +237,21 @@ class NetBoxRestClient(NetBoxClientBase):
     # })
     # print(f"Created site: {new_site.get('name')} (ID: {new_site.get('id')})")
 
-    def __init__(self, url: str, token: str, verify_ssl: bool = True):
+    def __init__(
+        self,
+        url: str,
+        token: str,
+        verify_ssl: bool = True,
+        max_retries: int = 3,
+        retry_backoff: float = 0.5,
+        cache_ttl: float = 0,
+        cache_size: int = 256,
+        proxy: str | None = None,
+        timeout: float = 30.0,
+        connect_timeout: float = 5.0,
+        auth_scheme: str = "auto",
+        extra_headers: dict[str, str] | None = None,
+    ):
         """
         Initialize the REST API client.
 
@@ -169,20 +259,52 @@ class NetBoxRestClient(NetBoxClientBase):
             url: The base URL of the NetBox instance (e.g., 'https://netbox.example.com')
             token: API token for authentication
             verify_ssl: Whether to verify SSL certificates
+            max_retries: How many times to retry a GET on 429/502/503/504 (0 disables retries)
+            retry_backoff: Base delay in seconds for jittered exponential backoff
+            cache_ttl: Seconds to cache GET responses for (0 disables caching)
+            cache_size: Maximum number of cached responses; least recently used are evicted
+            proxy: Optional proxy URL for all NetBox requests. When unset, the standard
+                   HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables apply.
+            timeout: Seconds to wait for NetBox to send or accept data, or for a pooled connection
+            connect_timeout: Seconds to wait for a connection to NetBox to be established
+            auth_scheme: Authorization scheme: "token", "bearer", or "auto" to use Bearer
+                         for v2 (nbt_) tokens and Token otherwise
+            extra_headers: Additional headers sent with every request, e.g. those required
+                           by a hosted NetBox gateway
         """
         self.base_url = url.rstrip("/")
         self.api_url = f"{self.base_url}/api"
         self.token = token
         self.verify_ssl = verify_ssl
-        auth_scheme = "Bearer" if token.startswith("nbt_") else "Token"
-        self.session = httpx.Client(verify=self.verify_ssl)
+        self.max_retries = max_retries
+        self.retry_backoff = retry_backoff
+        self.cache_ttl = cache_ttl
+        self.cache_size = cache_size
+        self._cache: OrderedDict[str, tuple[float, Any]] = OrderedDict()
+        # Tools run in worker threads, so every cache read and write happens under this lock.
+        self._cache_lock = threading.Lock()
+        # (monotonic time, seconds taken, status code or None on a transport error)
+        self._recent_requests: deque[tuple[float, float, int | None]] = deque(
+            maxlen=RECENT_REQUEST_WINDOW
+        )
+        self._recent_requests_lock = threading.Lock()
+        if auth_scheme == "auto":
+            auth_scheme = "bearer" if token.startswith("nbt_") else "token"
+        self.session = httpx.Client(
+            verify=self.verify_ssl,
+            proxy=proxy,
+            trust_env=True,
+            timeout=httpx.Timeout(timeout, connect=connect_timeout),
+        )
         self.session.headers.update(
             {
-                "Authorization": f"{auth_scheme} {token}",
+                "Authorization": f"{auth_scheme.capitalize()} {token}",
                 "Content-Type": "application/json",
                 "Accept": "application/json",
             }
         )
+        if extra_headers:
+            self.session.headers.update(extra_headers)
 
     def _build_url(self, endpoint: str, id: int | None = None) -> str:
         """Build the full URL for an API request."""
@@ -191,22 +313,138 @@ class NetBoxRestClient(NetBoxClientBase):
             return f"{self.api_url}/{endpoint}/{id}/"
         return f"{self.api_url}/{endpoint}/"
 
+    def _cache_key(
+        self,
+        endpoint: str,
+        id: int | None,
+        params: dict[str, Any] | None,
+        fallback_endpoint: str | None,
+    ) -> str:
+        """Build a cache key from everything that determines a GET response."""
+        return json.dumps(
+            [endpoint, id, params or {}, fallback_endpoint], sort_keys=True, default=str
+        )
+
+    def _cache_lookup(self, key: str) -> Any | None:
+        """Return a copy of a fresh cached response, or None on a miss or expiry."""
+        with self._cache_lock:
+            entry = self._cache.get(key)
+            if entry is None:
+                return None
+            expires_at, data = entry
+            if time.monotonic() >= expires_at:
+                del self._cache[key]
+                return None
+            self._cache.move_to_end(key)
+        return copy.deepcopy(data)
+
+    def _cache_store(self, key: str, data: Any) -> None:
+        """Cache a response, evicting the least recently used entries beyond cache_size."""
+        entry = (time.monotonic() + self.cache_ttl, copy.deepcopy(data))
+        with self._cache_lock:
+            self._cache[key] = entry
+            self._cache.move_to_end(key)
+            while len(self._cache) > self.cache_size:
+                self._cache.popitem(last=False)
+
+    def _get_with_retry(self, url: str, params: dict[str, Any] | None) -> httpx.Response:
+        """
+        Send a GET request, retrying transient failures with jittered exponential backoff.
+
+        A Retry-After header on the response takes precedence over the computed delay.
+        Only reads are retried; they are idempotent, so repeating them is safe.
+        """
+        for attempt in range(self.max_retries + 1):
+            response = self._timed_get(url, params)
+            if response.status_code not in RETRYABLE_STATUS_CODES or attempt == self.max_retries:
+                return response
+            delay = self._retry_delay(response, attempt)
+            logging.getLogger(__name__).warning(
+                f"NetBox returned {response.status_code} for {url}, retrying in {delay:.1f}s "
+                f"(attempt {attempt + 1} of {self.max_retries})",
+                extra={"status_code": response.status_code},
+            )
+            time.sleep(delay)
+        return response
+
+    def _timed_get(self, url: str, params: dict[str, Any] | None) -> httpx.Response:
+        """Send one GET, recording its latency and outcome for request_stats()."""
+        start = time.monotonic()
+        try:
+            response = self.session.get(url, params=params)
+        except httpx.TransportError:
+            self._record_request(start, None)
+            raise
+        self._record_request(start, response.status_code)
+        return response
+
+    def _record_request(self, start: float, status: int | None) -> None:
+        """Record a round trip for request_stats(); tools call in from several threads."""
+        with self._recent_requests_lock:
+            self._recent_requests.append((start, time.monotonic() - start, status))
+
+    def request_stats(self, window: float = 300) -> dict[str, Any]:
+        """
+        Summarize NetBox round trips (retries included, cache hits excluded) in a time window.
+
+        Errors are counted as defined by _is_integration_error.
+
+        Args:
+            window: How many seconds back to look
+
+        Returns:
+            Dict with request and error counts, error_rate, and latency_ms percentiles
+            (p50/p95/p99, None when there were no requests)
+        """
+        cutoff = time.monotonic() - window
+        with self._recent_requests_lock:
+            snapshot = list(self._recent_requests)
+        recent = [(took, status) for at, took, status in snapshot if at >= cutoff]
+        errors = sum(1 for _, status in recent if _is_integration_error(status))
+        latencies = sorted(took * 1000 for took, _ in recent)
+
+        def percentile(p: int) -> float | None:
+            """Nearest-rank percentile of the latencies."""
+            if not latencies:
+                return None
+            return round(latencies[math.ceil(len(latencies) * p / 100) - 1], 1)
+
+        return {
+            "requests": len(recent),
+            "errors": errors,
+            "error_rate": round(errors / len(recent), 3) if recent else 0.0,
+            "latency_ms": {"p50": percentile(50), "p95": percentile(95), "p99": percentile(99)},
+        }
+
+    def _retry_delay(self, response: httpx.Response, attempt: int) -> float:
+        """Seconds to wait before the next attempt, honouring Retry-After when present."""
+        delay = _parse_retry_after(response.headers.get("Retry-After"))
+        if delay is None:
+            delay = random.uniform(0, self.retry_backoff * 2**attempt)  # noqa: S311 - jitter only
+        return min(max(delay, 0.0), MAX_RETRY_DELAY)
+
     def get(
         self,
         endpoint: str,
         id: int | None = None,
         params: dict[str, Any] | None = None,
         fallback_endpoint: str | None = None,
+        use_cache: bool = True,
     ) -> dict[str, Any] | list[dict[str, Any]]:
         """
         Retrieve one or more objects from NetBox via the REST API.
 
+        When cache_ttl is set, identical requests are answered from the cache until
+        the entry expires, unless use_cache is False.
+
         Args:
             endpoint: The API endpoint (e.g., 'dcim/sites', 'ipam/prefixes')
             id: Optional ID to retrieve a specific object
             params: Optional query parameters for filtering
             fallback_endpoint: Optional alternative endpoint to try if primary returns 404
                                (used for NetBox version compatibility)
+            use_cache: Set to False to always fetch from NetBox, e.g. when polling;
+                       the fresh response still refreshes the cache
 
         Returns:
             For single object queries (with id): Returns the object dict
@@ -217,19 +455,44 @@ class NetBoxRestClient(NetBoxClientBase):
                 - results: Array of objects for this page
 
         Raises:
-            httpx.HTTPStatusError: If the request fails
+            httpx.HTTPStatusError: If the request fails (after retrying transient errors)
         """
-        url = self._build_url(endpoint, id)
-        response = self.session.get(url, params=params)
-
-        # Try fallback endpoint if primary returns 404
-        if response.status_code == 404 and fallback_endpoint:
-            fallback_url = self._build_url(fallback_endpoint, id)
-            response = self.session.get(fallback_url, params=params)
-
-        response.raise_for_status()
-
-        return response.json()
+        with tracer.start_as_current_span(
+            f"NetBox GET {_route_template(endpoint)}",
+            kind=trace.SpanKind.CLIENT,
+            attributes={"http.request.method": "GET", "netbox.endpoint": endpoint},
+        ) as span:
+            if id is not None:
+                span.set_attribute("netbox.object_id", id)
+
+            cache_key = None
+            if self.cache_ttl > 0:
+                cache_key = self._cache_key(endpoint, id, params, fallback_endpoint)
+                cached = self._cache_lookup(cache_key) if use_cache else None
+                span.set_attribute("netbox.cache_hit", cached is not None)
+                if cached is not None:
+                    return cached
+
+            url = self._build_url(endpoint, id)
+            response = self._get_with_retry(url, params)
+
+            # Try fallback endpoint if primary returns 404
+            if response.status_code == 404 and fallback_endpoint:
+                fallback_url = self._build_url(fallback_endpoint, id)
+                span.set_attribute("netbox.fallback_endpoint", fallback_endpoint)
+                response = self._get_with_retry(fallback_url, params)
+
+            span.set_attribute("http.response.status_code", response.status_code)
+            logging.getLogger(__name__).debug(
+                f"NetBox GET {endpoint} returned {response.status_code}",
+                extra={"endpoint": endpoint, "status_code": response.status_code},
+            )
+            response.raise_for_status()
+
+            data = response.json()
+            if cache_key is not None:
+                self._cache_store(cache_key, data)
+            return data
 
     def create(self, endpoint: str, data: dict[str, Any]) -> dict[str, Any]:
         """
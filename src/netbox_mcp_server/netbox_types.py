@@ -58,6 +58,7 @@ NETBOX_OBJECT_TYPES = {
     "core.objectchange": {
         "name": "ObjectChange",
         "endpoint": "core/object-changes",
+        "fallback_endpoint": "extras/object-changes",  # For NetBox < 4.1
     },
     "core.objecttype": {
         "name": "ObjectType",
@@ -1,19 +1,40 @@
 import argparse
 import asyncio
+import csv
+import datetime
 import hashlib
 import hmac
+import io
+import ipaddress
+import json
 import logging
+import re
+import ssl
 import sys
-from typing import Annotated, Any
+import threading
+import time
+import uuid
+from collections import Counter, OrderedDict, deque
+from collections.abc import Awaitable, Callable
+from contextvars import ContextVar
+from typing import Annotated, Any, Literal
+from zoneinfo import ZoneInfo
 
 import httpx
+import yaml
 from fastmcp import FastMCP
-from fastmcp.server.auth import AccessToken, TokenVerifier
-from pydantic import Field, SecretStr
+from fastmcp.exceptions import NotFoundError, ToolError
+from fastmcp.server.auth import AccessToken, AuthProvider, RemoteAuthProvider, TokenVerifier
+from fastmcp.server.auth.providers.jwt import JWTVerifier
+from fastmcp.server.middleware import Middleware as MCPMiddleware
+from fastmcp.server.middleware import MiddlewareContext
+from pydantic import AnyHttpUrl, Field, SecretStr
 from starlette.middleware import Middleware
 from starlette.middleware.cors import CORSMiddleware
+from starlette.requests import Request
+from starlette.responses import JSONResponse
 
-from netbox_mcp_server.config import Settings, configure_logging
+from netbox_mcp_server.config import Settings, configure_logging, request_id_var
 from netbox_mcp_server.netbox_client import NetBoxRestClient
 from netbox_mcp_server.netbox_types import NETBOX_OBJECT_TYPES
 
@@ -41,6 +62,53 @@ def parse_cli_args() -> dict[str, Any]:
         type=str,
         help="API token for NetBox authentication",
     )
+    parser.add_argument(
+        "--netbox-auth-scheme",
+        type=str,
+        choices=["auto", "token", "bearer"],
+        help="Authorization scheme for NetBox (default: auto, Bearer for nbt_ tokens)",
+    )
+    parser.add_argument(
+        "--netbox-header",
+        action="append",
+        metavar="NAME=VALUE",
+        help="Extra header sent with every NetBox request (repeat flag; default: none)",
+    )
+    parser.add_argument(
+        "--netbox-max-retries",
+        type=int,
+        help="Retries for NetBox reads failing with 429/502/503/504 (default: 3)",
+    )
+    parser.add_argument(
+        "--netbox-retry-backoff",
+        type=float,
+        help="Base backoff delay in seconds between retries (default: 0.5)",
+    )
+    parser.add_argument(
+        "--netbox-cache-ttl",
+        type=float,
+        help="Seconds to cache NetBox GET responses (default: 0, disabled)",
+    )
+    parser.add_argument(
+        "--netbox-cache-size",
+        type=int,
+        help="Maximum number of cached NetBox responses (default: 256)",
+    )
+    parser.add_argument(
+        "--netbox-proxy",
+        type=str,
+        help="Proxy URL for NetBox requests (default: HTTP_PROXY/HTTPS_PROXY env vars)",
+    )
+    parser.add_argument(
+        "--netbox-timeout",
+        type=float,
+        help="Seconds to wait on NetBox reads and writes (default: 30)",
+    )
+    parser.add_argument(
+        "--netbox-connect-timeout",
+        type=float,
+        help="Seconds to wait for a connection to NetBox (default: 5)",
+    )
 
     # Transport settings
     parser.add_argument(
@@ -72,6 +140,46 @@ def parse_cli_args() -> dict[str, Any]:
             "(prefer the MCP_AUTH_TOKEN env var; default: none)"
         ),
     )
+    parser.add_argument(
+        "--tls-certfile",
+        type=str,
+        help="PEM server certificate; serves the HTTP transport over HTTPS (default: none)",
+    )
+    parser.add_argument(
+        "--tls-keyfile",
+        type=str,
+        help="PEM private key for --tls-certfile",
+    )
+    parser.add_argument(
+        "--tls-client-ca-file",
+        type=str,
+        help="PEM CA bundle; require client certificates signed by it (mTLS)",
+    )
+    parser.add_argument(
+        "--mcp-oauth-issuer",
+        type=str,
+        help="OAuth 2.0 / OIDC issuer whose JWTs are accepted on the HTTP endpoint",
+    )
+    parser.add_argument(
+        "--mcp-oauth-jwks-uri",
+        type=str,
+        help="JWKS URL used to verify JWT signatures (required with --mcp-oauth-issuer)",
+    )
+    parser.add_argument(
+        "--mcp-oauth-audience",
+        type=str,
+        help="Expected JWT audience claim (default: not checked)",
+    )
+    parser.add_argument(
+        "--mcp-oauth-required-scopes",
+        action="append",
+        help="Scope every JWT must carry (repeat flag; default: none)",
+    )
+    parser.add_argument(
+        "--mcp-base-url",
+        type=str,
+        help="Public URL of this server for OAuth metadata (required with --mcp-oauth-issuer)",
+    )
 
     # Security settings
     ssl_group = parser.add_mutually_exclusive_group()
@@ -98,6 +206,49 @@ def parse_cli_args() -> dict[str, Any]:
         help="Auto-discover plugin object types from NetBox at startup",
     )
 
+    # Tool selection settings
+    parser.add_argument(
+        "--enabled-tools",
+        action="append",
+        help="Only expose these tools (repeat flag; default: all tools)",
+    )
+    parser.add_argument(
+        "--disabled-tools",
+        action="append",
+        help="Hide these tools (repeat flag; default: none)",
+    )
+    parser.add_argument(
+        "--tool-overrides-file",
+        type=str,
+        help="JSON file overriding or extending tool and parameter descriptions",
+    )
+
+    # Saved query settings
+    parser.add_argument(
+        "--saved-queries-file",
+        type=str,
+        help="JSON file of named, parameterized queries exposed as tools (default: none)",
+    )
+
+    # Response formatting settings
+    parser.add_argument(
+        "--normalize-units",
+        action="store_true",
+        default=None,
+        dest="normalize_units",
+        help="Add converted unit fields (e.g. speed_gbps, maximum_draw_kw) to responses",
+    )
+    parser.add_argument(
+        "--max-response-chars",
+        type=int,
+        help="Summarize responses larger than this many JSON characters (default: 0, off)",
+    )
+    parser.add_argument(
+        "--display-timezone",
+        type=str,
+        help="IANA timezone to convert response timestamps into (default: as returned, UTC)",
+    )
+
     # Observability settings
     parser.add_argument(
         "--log-level",
@@ -105,6 +256,12 @@ def parse_cli_args() -> dict[str, Any]:
         choices=["DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL"],
         help="Logging verbosity level (default: INFO)",
     )
+    parser.add_argument(
+        "--log-format",
+        type=str,
+        choices=["text", "json"],
+        help="Log output format (default: text)",
+    )
 
     args: argparse.Namespace = parser.parse_args()
 
@@ -113,6 +270,30 @@ def parse_cli_args() -> dict[str, Any]:
         overlay["netbox_url"] = args.netbox_url
     if args.netbox_token is not None:
         overlay["netbox_token"] = args.netbox_token
+    if args.netbox_auth_scheme is not None:
+        overlay["netbox_auth_scheme"] = args.netbox_auth_scheme
+    if args.netbox_header is not None:
+        headers = {}
+        for header in args.netbox_header:
+            name, sep, value = header.partition("=")
+            if not sep or not name.strip():
+                parser.error(f"--netbox-header must be NAME=VALUE, got {header!r}")
+            headers[name.strip()] = value
+        overlay["netbox_extra_headers"] = headers
+    if args.netbox_max_retries is not None:
+        overlay["netbox_max_retries"] = args.netbox_max_retries
+    if args.netbox_retry_backoff is not None:
+        overlay["netbox_retry_backoff"] = args.netbox_retry_backoff
+    if args.netbox_cache_ttl is not None:
+        overlay["netbox_cache_ttl"] = args.netbox_cache_ttl
+    if args.netbox_cache_size is not None:
+        overlay["netbox_cache_size"] = args.netbox_cache_size
+    if args.netbox_proxy is not None:
+        overlay["netbox_proxy"] = args.netbox_proxy
+    if args.netbox_timeout is not None:
+        overlay["netbox_timeout"] = args.netbox_timeout
+    if args.netbox_connect_timeout is not None:
+        overlay["netbox_connect_timeout"] = args.netbox_connect_timeout
     if args.transport is not None:
         overlay["transport"] = args.transport
     if args.host is not None:
@@ -123,12 +304,44 @@ def parse_cli_args() -> dict[str, Any]:
         overlay["cors_origins"] = args.cors_origins
     if args.mcp_auth_token is not None:
         overlay["mcp_auth_token"] = args.mcp_auth_token
+    if args.tls_certfile is not None:
+        overlay["tls_certfile"] = args.tls_certfile
+    if args.tls_keyfile is not None:
+        overlay["tls_keyfile"] = args.tls_keyfile
+    if args.tls_client_ca_file is not None:
+        overlay["tls_client_ca_file"] = args.tls_client_ca_file
+    if args.mcp_oauth_issuer is not None:
+        overlay["mcp_oauth_issuer"] = args.mcp_oauth_issuer
+    if args.mcp_oauth_jwks_uri is not None:
+        overlay["mcp_oauth_jwks_uri"] = args.mcp_oauth_jwks_uri
+    if args.mcp_oauth_audience is not None:
+        overlay["mcp_oauth_audience"] = args.mcp_oauth_audience
+    if args.mcp_oauth_required_scopes is not None:
+        overlay["mcp_oauth_required_scopes"] = args.mcp_oauth_required_scopes
+    if args.mcp_base_url is not None:
+        overlay["mcp_base_url"] = args.mcp_base_url
     if args.verify_ssl is not None:
         overlay["verify_ssl"] = args.verify_ssl
     if args.enable_plugin_discovery is not None:
         overlay["enable_plugin_discovery"] = args.enable_plugin_discovery
+    if args.enabled_tools is not None:
+        overlay["enabled_tools"] = args.enabled_tools
+    if args.disabled_tools is not None:
+        overlay["disabled_tools"] = args.disabled_tools
+    if args.tool_overrides_file is not None:
+        overlay["tool_overrides_file"] = args.tool_overrides_file
+    if args.saved_queries_file is not None:
+        overlay["saved_queries_file"] = args.saved_queries_file
+    if args.normalize_units is not None:
+        overlay["normalize_units"] = args.normalize_units
+    if args.max_response_chars is not None:
+        overlay["max_response_chars"] = args.max_response_chars
+    if args.display_timezone is not None:
+        overlay["display_timezone"] = args.display_timezone
     if args.log_level is not None:
         overlay["log_level"] = args.log_level
+    if args.log_format is not None:
+        overlay["log_format"] = args.log_format
 
     return overlay
 
@@ -179,6 +392,253 @@ def build_http_auth(token: SecretStr | None) -> TokenVerifier | None:
     return BearerTokenVerifier(token.get_secret_value())
 
 
+def build_oauth_auth(settings: Settings) -> AuthProvider | None:
+    """
+    Build an OAuth 2.0 resource-server auth provider from the MCP_OAUTH_* settings.
+
+    Incoming bearers are verified as JWTs against the issuer's JWKS (signature,
+    issuer, expiry and, when configured, audience and required scopes). The
+    returned provider also serves /.well-known/oauth-protected-resource so MCP
+    clients can discover the issuer, per the MCP authorization spec. This server
+    never issues tokens; the corporate IdP does.
+
+    Args:
+        settings: Validated settings; OAuth is enabled when mcp_oauth_issuer is set
+
+    Returns:
+        A RemoteAuthProvider, or None when no OAuth issuer is configured
+    """
+    if not settings.mcp_oauth_issuer:
+        return None
+    verifier = JWTVerifier(
+        jwks_uri=settings.mcp_oauth_jwks_uri,
+        issuer=settings.mcp_oauth_issuer,
+        audience=settings.mcp_oauth_audience,
+        required_scopes=settings.mcp_oauth_required_scopes or None,
+    )
+    return RemoteAuthProvider(
+        token_verifier=verifier,
+        authorization_servers=[AnyHttpUrl(settings.mcp_oauth_issuer)],
+        base_url=settings.mcp_base_url,
+        resource_name="NetBox MCP Server",
+    )
+
+
+def build_tls_config(settings: Settings) -> dict[str, Any]:
+    """
+    Build uvicorn SSL options for the HTTP transport from the TLS_* settings.
+
+    With a client CA, the TLS handshake itself rejects clients that do not present a
+    certificate signed by that CA, before any MCP request is read.
+
+    Args:
+        settings: Validated settings
+
+    Returns:
+        uvicorn config keyword arguments, empty when TLS is not configured
+    """
+    if not settings.tls_certfile:
+        return {}
+    config: dict[str, Any] = {
+        "ssl_certfile": settings.tls_certfile,
+        "ssl_keyfile": settings.tls_keyfile,
+    }
+    if settings.tls_client_ca_file:
+        config["ssl_ca_certs"] = settings.tls_client_ca_file
+        config["ssl_cert_reqs"] = ssl.CERT_REQUIRED
+    return config
+
+
+def classify_error(error: Exception) -> dict[str, Any]:
+    """
+    Describe a tool failure as a machine-readable error object.
+
+    Lets agent frameworks branch on the kind of failure instead of parsing prose.
+
+    Args:
+        error: The exception raised while running a tool
+
+    Returns:
+        Dict with category (validation, auth, not_found, rate_limit or server),
+        message, retryable flag and a suggested next action
+    """
+    if isinstance(error, httpx.HTTPStatusError):
+        status = error.response.status_code
+        if status in (401, 403):
+            category, retryable = "auth", False
+            action = "Check the NetBox token is valid and has read permission for this object type."
+        elif status == 404:
+            category, retryable = "not_found", False
+            action = "Verify the object ID and object_type exist, e.g. with netbox_get_objects."
+        elif status == 429:
+            category, retryable = "rate_limit", True
+            action = "Wait before retrying and reduce the number of parallel requests."
+        elif status >= 500:
+            category, retryable = "server", True
+            action = "Retry later; NetBox returned a server error."
+        else:
+            category, retryable = "validation", False
+            action = "Fix the filters or parameters according to the message and retry."
+    elif isinstance(error, httpx.TimeoutException | httpx.NetworkError):
+        category, retryable = "server", True
+        action = "Retry later; NetBox could not be reached in time."
+    elif isinstance(error, ValueError):
+        category, retryable = "validation", False
+        action = "Fix the arguments according to the message and retry."
+    else:
+        category, retryable = "server", False
+        action = "Report this error; it is not caused by the request arguments."
+
+    return {
+        "category": category,
+        "message": str(error),
+        "retryable": retryable,
+        "suggested_action": action,
+    }
+
+
+class StructuredErrorMiddleware(MCPMiddleware):
+    """Attach a machine-readable error object to every failed tool call."""
+
+    async def on_call_tool(
+        self,
+        context: MiddlewareContext,
+        call_next: Callable[[MiddlewareContext], Awaitable[Any]],
+    ) -> Any:
+        """Run the tool, re-raising any failure as a ToolError carrying JSON."""
+        try:
+            return await call_next(context)
+        except Exception as e:
+            # FastMCP wraps tool exceptions in ToolError; classify the original cause
+            original = e.__cause__ if isinstance(e, ToolError) and e.__cause__ else e
+            raise ToolError(json.dumps({"error": classify_error(original)})) from original
+
+
+# (monotonic time, succeeded) for recent tool calls, reported by netbox_get_server_health.
+# Appended on the event loop and read from tool worker threads, so guarded by a lock.
+_recent_tool_calls: deque[tuple[float, bool]] = deque(maxlen=1000)
+_recent_tool_calls_lock = threading.Lock()
+
+
+def _record_tool_call(succeeded: bool) -> None:
+    """Record a tool call outcome for netbox_get_server_health."""
+    with _recent_tool_calls_lock:
+        _recent_tool_calls.append((time.monotonic(), succeeded))
+
+
+class ToolCallLoggingMiddleware(MCPMiddleware):
+    """Log every tool call with its outcome and duration under a per-call request ID."""
+
+    async def on_call_tool(
+        self,
+        context: MiddlewareContext,
+        call_next: Callable[[MiddlewareContext], Awaitable[Any]],
+    ) -> Any:
+        """Run the tool, logging completion or failure with structured fields."""
+        logger = logging.getLogger(__name__)
+        token = request_id_var.set(uuid.uuid4().hex[:16])
+        tool = context.message.name
+        extra = {"tool": tool, "object_type": (context.message.arguments or {}).get("object_type")}
+        start = time.perf_counter()
+        try:
+            result = await call_next(context)
+        except Exception as e:
+            original = e.__cause__ if isinstance(e, ToolError) and e.__cause__ else e
+            duration_ms = round((time.perf_counter() - start) * 1000, 1)
+            category = classify_error(original)["category"]
+            _record_tool_call(False)
+            logger.warning(
+                f"Tool {tool} failed after {duration_ms}ms ({category})",
+                extra={
+                    **extra,
+                    "status": "error",
+                    "error_category": category,
+                    "duration_ms": duration_ms,
+                },
+            )
+            raise
+        else:
+            duration_ms = round((time.perf_counter() - start) * 1000, 1)
+            _record_tool_call(True)
+            logger.info(
+                f"Tool {tool} completed in {duration_ms}ms",
+                extra={**extra, "status": "ok", "duration_ms": duration_ms},
+            )
+            return result
+        finally:
+            request_id_var.reset(token)
+
+
+# Log records emitted while the current tool call runs, forwarded to the MCP client after it
+_client_log_records: ContextVar[list[logging.LogRecord] | None] = ContextVar(
+    "client_log_records", default=None
+)
+
+# Python logging level -> MCP logging notification level
+MCP_LOG_LEVELS = {
+    logging.DEBUG: "debug",
+    logging.INFO: "info",
+    logging.WARNING: "warning",
+    logging.ERROR: "error",
+    logging.CRITICAL: "critical",
+}
+
+
+class _ClientLogCaptureHandler(logging.Handler):
+    """Collect records into the running tool call's buffer, if any."""
+
+    def emit(self, record: logging.LogRecord) -> None:
+        records = _client_log_records.get()
+        if records is not None:
+            records.append(record)
+
+
+_client_log_handler = _ClientLogCaptureHandler()
+
+
+class ClientLogForwardingMiddleware(MCPMiddleware):
+    """Forward the server's log records for each tool call to the client as MCP notifications.
+
+    Covers everything logged under netbox_mcp_server during the call: tool dispatch and
+    outcome, NetBox requests at DEBUG and retries. Records are sent once the call ends;
+    FastMCP drops those below the level the client chose with logging/setLevel.
+    """
+
+    def __init__(self) -> None:
+        super().__init__()
+        # One shared handler: addHandler ignores repeats, so records are never captured twice
+        logging.getLogger("netbox_mcp_server").addHandler(_client_log_handler)
+
+    async def on_call_tool(
+        self,
+        context: MiddlewareContext,
+        call_next: Callable[[MiddlewareContext], Awaitable[Any]],
+    ) -> Any:
+        """Run the tool with log capture on, then forward what was captured."""
+        records: list[logging.LogRecord] = []
+        token = _client_log_records.set(records)
+        try:
+            return await call_next(context)
+        finally:
+            _client_log_records.reset(token)
+            await self._forward(context, records)
+
+    async def _forward(self, context: MiddlewareContext, records: list[logging.LogRecord]) -> None:
+        """Send records to the client; a closed session must not fail the tool call."""
+        ctx = context.fastmcp_context
+        if ctx is None:
+            return
+        for record in records:
+            try:
+                await ctx.log(
+                    record.getMessage(),
+                    level=MCP_LOG_LEVELS.get(record.levelno, "info"),
+                    logger_name=record.name,
+                )
+            except Exception:  # noqa: S112 - best effort; the call result matters more
+                continue
+
+
 # Default object types for global search
 DEFAULT_SEARCH_TYPES = [
     "dcim.device",  # Most common search target
@@ -191,8 +651,159 @@ DEFAULT_SEARCH_TYPES = [
     "virtualization.virtualmachine",  # VM names
 ]
 
-mcp = FastMCP("NetBox")
+# Object types with a cable trace endpoint, mapped to the endpoint's action.
+# Pass-through ports can fan out to several paths, so NetBox exposes "paths"
+# for them instead of a single "trace".
+CABLE_TRACE_TYPES = {
+    "dcim.interface": "trace",
+    "dcim.consoleport": "trace",
+    "dcim.consoleserverport": "trace",
+    "dcim.powerport": "trace",
+    "dcim.poweroutlet": "trace",
+    "dcim.powerfeed": "trace",
+    "dcim.frontport": "paths",
+    "dcim.rearport": "paths",
+}
+
+# Interface types that are never cabled, so they never count as free ports
+NON_PHYSICAL_INTERFACE_TYPES = {"virtual", "bridge", "lag"}
+
+# Interface fields needed to classify ports as free, connected or disabled
+INTERFACE_SUMMARY_FIELDS = "id,name,type,speed,duplex,lag,enabled,cable,mark_connected"
+
+# NetBox error rate and p95 latency at which netbox_get_server_health reports "degraded"
+DEGRADED_ERROR_RATE = 0.1
+DEGRADED_P95_MS = 5000
+
+# How long the deployment summary resource is served before it is rebuilt
+DEPLOYMENT_SUMMARY_TTL = 300
+
+# Full responses kept readable as netbox://results/{id} after MAX_RESPONSE_CHARS truncation
+SCRATCHPAD_MAX_ENTRIES = 20
+
+# NetBox fields converted when NORMALIZE_UNITS is on: field -> (new field suffix, factor)
+UNIT_CONVERSIONS = {
+    "speed": ("gbps", 1e-6),  # Interface speed in Kbps
+    "commit_rate": ("gbps", 1e-6),  # Circuit commit rate in Kbps
+    "maximum_draw": ("kw", 1e-3),  # Power port draw in W
+    "allocated_draw": ("kw", 1e-3),
+}
+
+# Fields every change touches, left out of netbox_diff_change output
+DIFF_IGNORED_FIELDS = {"last_updated"}
+
+# core.job statuses after which a job will not change again
+JOB_FINISHED_STATUSES = {"completed", "errored", "failed"}
+
+# Webhook fields shown by netbox_get_event_pipelines; secret and additional_headers are
+# left out since they commonly hold credentials
+WEBHOOK_SUMMARY_FIELDS = (
+    "id",
+    "name",
+    "payload_url",
+    "http_method",
+    "http_content_type",
+    "ssl_verification",
+)
+
+# netbox_audit_data_quality checks: name -> (priority, object type, NetBox filters,
+# site filter name or None when the type can't be filtered by site, issue detail)
+DATA_QUALITY_CHECKS: dict[str, tuple[str, str, dict[str, Any], str | None, str]] = {
+    "device_without_primary_ip": (
+        "medium",
+        "dcim.device",
+        {"has_primary_ip": "false"},
+        "site_id",
+        "Device has no primary IP",
+    ),
+    "vm_without_primary_ip": (
+        "medium",
+        "virtualization.virtualmachine",
+        {"has_primary_ip": "false"},
+        "site_id",
+        "Virtual machine has no primary IP",
+    ),
+    "device_without_serial": (
+        "low",
+        "dcim.device",
+        {"serial__empty": "true"},
+        "site_id",
+        "Device has no serial number",
+    ),
+    "device_without_platform": (
+        "low",
+        "dcim.device",
+        {"platform_id": "null"},
+        "site_id",
+        "Device has no platform",
+    ),
+    "interface_type_other": (
+        "low",
+        "dcim.interface",
+        {"type": "other"},
+        "site_id",
+        'Interface type is "other"',
+    ),
+    "ip_without_assignment": (
+        "low",
+        "ipam.ipaddress",
+        {"assigned_to_interface": "false"},
+        None,
+        "IP address is not assigned to an interface",
+    ),
+}
+
+# How many PDUs deep netbox_trace_power_chain follows outlets before giving up
+MAX_POWER_CHAIN_DEPTH = 5
+
+POWER_PORT_FIELDS = "id,name,device,connected_endpoints,connected_endpoints_type"
+
+# Where netbox_find_contacts_for_object also looks for contacts: object type ->
+# (field holding the related object, related object type)
+RELATED_CONTACT_SOURCES = {
+    "dcim.device": ("site", "dcim.site"),
+    "dcim.rack": ("site", "dcim.site"),
+    "virtualization.virtualmachine": ("site", "dcim.site"),
+    "circuits.circuit": ("provider", "circuits.provider"),
+}
+
+CONTACT_PRIORITY_ORDER = {"primary": 0, "secondary": 1, "tertiary": 2, None: 3, "inactive": 4}
+
+# IP address roles NetBox allows to share an address within a VRF
+NONUNIQUE_IP_ROLES = {"anycast", "vip", "vrrp", "hsrp", "glbp", "carp"}
+
+# Event rule flags used instead of event_types before NetBox 4.1
+LEGACY_EVENT_TYPE_FLAGS = (
+    "type_create",
+    "type_update",
+    "type_delete",
+    "type_job_start",
+    "type_job_end",
+)
+
+# {name} placeholders in saved query filter values, filled from run-time parameters
+PLACEHOLDER_PATTERN = re.compile(r"\{(\w+)\}")
+
+# ISO 8601 timestamp fields converted into DISPLAY_TIMEZONE when it is set
+TIMESTAMP_FIELDS = {"created", "last_updated", "time", "last_synced", "data_synced"}
+
+mcp = FastMCP(
+    "NetBox",
+    middleware=[
+        StructuredErrorMiddleware(),
+        ClientLogForwardingMiddleware(),
+        ToolCallLoggingMiddleware(),
+    ],
+)
 netbox = None
+normalize_units = False
+display_timezone: ZoneInfo | None = None
+saved_queries: dict[str, dict[str, Any]] = {}
+max_response_chars = 0
+_deployment_summary_cache: tuple[float, dict[str, Any]] | None = None
+_deployment_summary_lock = threading.Lock()
+_scratchpad: OrderedDict[str, Any] = OrderedDict()
+_scratchpad_lock = threading.Lock()
 
 
 def validate_filters(filters: dict) -> None:
@@ -271,6 +882,9 @@ def validate_filters(filters: dict) -> None:
 
     Args:
         object_type: String representing the NetBox object type (e.g. "dcim.device", "ipam.ipaddress")
+                     or a list of types to query with the same filters in one call, e.g.
+                     ["dcim.device", "virtualization.virtualmachine"] for devices and VMs
+                     named web-*. Results are merged in the order given (see Returns).
         filters: dict of filters to apply to the API call based on the NetBox API filtering options
 
                 FILTER RULES:
@@ -323,6 +937,18 @@ def validate_filters(filters: dict) -> None:
                   - ['facility', '-name'] (by facility, then by name descending)
                   - None, '' or [] (default NetBox ordering)
 
+        output_format: "json" (default), "yaml", "csv" or "markdown". "yaml" returns the same
+                       response as YAML text, which takes fewer tokens for medium-sized
+                       result sets. With "csv" or "markdown", results is replaced by a csv
+                       or markdown table string with one column per field (fields order
+                       first). Related objects become their display name, choices their
+                       value and lists are joined with "; ". Use csv when the user wants a
+                       spreadsheet and markdown for tables to paste into tickets or chat.
+
+        count_only: Return only the number of matching objects, e.g. for "how many active
+                    devices are in DC1?". Fields, brief, limit, offset, ordering and
+                    output_format are ignored. Much cheaper than fetching records to count.
+
 
     Returns:
         Paginated response dict with the following structure:
@@ -334,6 +960,15 @@ def validate_filters(filters: dict) -> None:
                         ALWAYS REFER TO THIS FIELD FOR THE PREVIOUS PAGE OF RESULTS
             - results: Array of objects for this page
                        ALWAYS REFER TO THIS FIELD FOR THE OBJECTS ON THIS PAGE
+            - csv / markdown: Replaces results when output_format is "csv" or "markdown"
+
+        For a list of object types, a merged dict instead:
+            - count: Total matches across all types
+            - counts: Total matches per type
+            - results: Each type's page (limit/offset apply per type), in the order the types
+                       were given, with every object labelled by an added "_object_type" key
+
+        With count_only, just {"count": N} ({"count": N, "counts": {...}} for a list of types).
 
     ENSURE YOU ARE AWARE THE RESULTS ARE PAGINATED BEFORE PROVIDING RESPONSE TO THE USER.
 
@@ -347,21 +982,56 @@ def validate_filters(filters: dict) -> None:
     """
 )
 def netbox_get_objects(
-    object_type: str,
+    object_type: str | list[str],
     filters: dict,
     fields: list[str] | None = None,
     brief: bool = False,
     limit: Annotated[int, Field(default=5, ge=1, le=100)] = 5,
     offset: Annotated[int, Field(default=0, ge=0)] = 0,
     ordering: str | list[str] | None = None,
+    output_format: Literal["json", "yaml", "csv", "markdown"] = "json",
+    count_only: bool = False,
 ):
     """
     Get objects from NetBox based on their type and filters
     """
-    # Validate object_type exists in mapping
-    if object_type not in NETBOX_OBJECT_TYPES:
-        valid_types = "\n".join(f"- {t}" for t in sorted(NETBOX_OBJECT_TYPES.keys()))
-        raise ValueError(f"Invalid object_type. Must be one of:\n{valid_types}")
+    query = {
+        "filters": filters,
+        "fields": fields,
+        "brief": brief,
+        "limit": limit,
+        "offset": offset,
+        "ordering": ordering,
+    }
+    if count_only:
+        # Fetch a single brief object: NetBox computes count over the full result set
+        query.update(fields=None, brief=True, limit=1, offset=0, ordering=None)
+    if isinstance(object_type, list):
+        response = _get_objects_for_types(object_type, **query)
+    else:
+        response = _get_objects(object_type, **query)
+    if count_only:
+        return {key: response[key] for key in ("count", "counts") if key in response}
+    if output_format in ("csv", "markdown"):
+        to_table = _results_to_csv if output_format == "csv" else _results_to_markdown
+        # Size the table the caller receives, not the much larger JSON it is built from
+        return _limit_response_size(
+            response, render=lambda results: {output_format: to_table(results, fields)}
+        )
+    return _format_response(_limit_response_size(response), output_format)
+
+
+def _get_objects(
+    object_type: str,
+    filters: dict,
+    fields: list[str] | None,
+    brief: bool,
+    limit: int,
+    offset: int,
+    ordering: str | list[str] | None,
+) -> dict[str, Any]:
+    """Fetch one page of a single object type; the body of netbox_get_objects."""
+    _validate_object_type(object_type)
 
     # Validate filter patterns
     validate_filters(filters)
@@ -387,7 +1057,73 @@ def netbox_get_objects(
             params["ordering"] = ordering
 
     # Make API call
-    return netbox.get(endpoint, params=params, fallback_endpoint=fallback)
+    return _normalize_response(netbox.get(endpoint, params=params, fallback_endpoint=fallback))
+
+
+def _get_objects_for_types(object_types: list[str], **query: Any) -> dict[str, Any]:
+    """Fetch a page of each type and merge them, labelling objects by type."""
+    if not object_types:
+        raise ValueError("object_type list must contain at least one type")
+    # Validate every type before querying any, so a typo doesn't cost partial results
+    for object_type in object_types:
+        _validate_object_type(object_type)
+
+    counts: dict[str, int] = {}
+    results: list[dict[str, Any]] = []
+    for object_type in dict.fromkeys(object_types):
+        page = _get_objects(object_type, **query)
+        counts[object_type] = page.get("count", 0)
+        results.extend({**obj, "_object_type": object_type} for obj in page.get("results", []))
+    return {"count": sum(counts.values()), "counts": counts, "results": results}
+
+
+def _format_response(data: Any, output_format: str) -> Any:
+    """Return data unchanged for JSON output, or as block-style YAML text."""
+    if output_format != "yaml":
+        return data
+    return yaml.safe_dump(data, sort_keys=False, allow_unicode=True)
+
+
+def _results_to_csv(results: list[dict[str, Any]], fields: list[str] | None) -> str:
+    """Render objects as CSV with a header row, requested fields first."""
+    columns = _result_columns(results, fields)
+    buffer = io.StringIO()
+    writer = csv.writer(buffer, lineterminator="\n")
+    writer.writerow(columns)
+    for obj in results:
+        writer.writerow([_cell_value(obj.get(column)) for column in columns])
+    return buffer.getvalue()
+
+
+def _results_to_markdown(results: list[dict[str, Any]], fields: list[str] | None) -> str:
+    """Render objects as a markdown table, requested fields first."""
+    columns = _result_columns(results, fields)
+    if not columns:
+        return ""
+    rows = [columns, ["---"] * len(columns)]
+    for obj in results:
+        cells = (str(_cell_value(obj.get(column))) for column in columns)
+        rows.append([cell.replace("|", "\\|").replace("\n", " ") for cell in cells])
+    return "".join(f"| {' | '.join(row)} |\n" for row in rows)
+
+
+def _result_columns(results: list[dict[str, Any]], fields: list[str] | None) -> list[str]:
+    """Table columns: the requested fields in order, then any other keys as first seen."""
+    return list(dict.fromkeys([*(fields or []), *(key for obj in results for key in obj)]))
+
+
+def _cell_value(value: Any) -> Any:
+    """Flatten a field for a table cell: related objects to their name, choices to their value."""
+    if value is None:
+        return ""
+    if isinstance(value, list):
+        return "; ".join(str(_cell_value(item)) for item in value)
+    if isinstance(value, dict):
+        for key in ("display", "name", "value"):
+            if key in value:
+                return value[key]
+        return json.dumps(value, default=str)
+    return value
 
 
 @mcp.tool
@@ -396,6 +1132,7 @@ def netbox_get_object_by_id(
     object_id: int,
     fields: list[str] | None = None,
     brief: bool = False,
+    output_format: Literal["json", "yaml"] = "json",
 ):
     """
     Get detailed information about a specific NetBox object by its ID.
@@ -419,14 +1156,13 @@ def netbox_get_object_by_id(
                 **Always specify only the fields you actually need.**
         brief: returns only a minimal representation of the object in the response.
                This is useful when you need only a summary of the object without any related data.
+        output_format: "json" (default) or "yaml" to return the object as YAML text,
+                       which takes fewer tokens
 
     Returns:
         Object dict (complete or with only requested fields based on fields parameter)
     """
-    # Validate object_type exists in mapping
-    if object_type not in NETBOX_OBJECT_TYPES:
-        valid_types = "\n".join(f"- {t}" for t in sorted(NETBOX_OBJECT_TYPES.keys()))
-        raise ValueError(f"Invalid object_type. Must be one of:\n{valid_types}")
+    _validate_object_type(object_type)
 
     # Get API endpoint and fallback from mapping
     endpoint, fallback = _get_endpoint_info(object_type)
@@ -440,16 +1176,79 @@ def netbox_get_object_by_id(
     if brief:
         params["brief"] = "1"
 
-    return netbox.get(full_endpoint, params=params, fallback_endpoint=full_fallback)
+    return _format_response(
+        _limit_response_size(
+            _normalize_response(
+                netbox.get(full_endpoint, params=params, fallback_endpoint=full_fallback)
+            )
+        ),
+        output_format,
+    )
+
+
+@mcp.tool
+def netbox_aggregate_objects(
+    object_type: str,
+    filters: dict,
+    group_by: str | list[str],
+    limit: Annotated[int, Field(default=50, ge=1, le=1000)] = 50,
+) -> dict[str, Any]:
+    """
+    Count objects per group, e.g. devices per role or devices per role per site.
+
+    Pages through every matching object server-side, so counts are exact and no records
+    are returned. Prefer this over paging netbox_get_objects results to count them.
+
+    Args:
+        object_type: NetBox object type (e.g. "dcim.device"), as for netbox_get_objects
+        filters: Filters to apply first, with the same rules as netbox_get_objects
+        group_by: Field, or list of fields for nested grouping, e.g. "role" or
+                  ["site", "role"]. Related objects group by display name, choices by
+                  value; objects with no value are grouped under null.
+        limit: Maximum number of groups to return, largest first (default 50, max 1000)
+
+    Returns:
+        Dict with:
+            - total: Number of objects counted
+            - group_count: Number of distinct groups
+            - groups: Largest groups first, each with the group_by fields and a count
+    """
+    _validate_object_type(object_type)
+    validate_filters(filters)
+    group_fields = [group_by] if isinstance(group_by, str) else group_by
+    if not group_fields:
+        raise ValueError("group_by must name at least one field")
+
+    endpoint, fallback = _get_endpoint_info(object_type)
+    objects = _get_all_objects(endpoint, {**filters, "fields": ",".join(group_fields)}, fallback)
+    counts = Counter(
+        tuple(_group_value(obj.get(field)) for field in group_fields) for obj in objects
+    )
+
+    return {
+        "total": len(objects),
+        "group_count": len(counts),
+        "groups": [
+            {**dict(zip(group_fields, key, strict=True)), "count": count}
+            for key, count in counts.most_common(limit)
+        ],
+    }
+
+
+def _group_value(value: Any) -> Any:
+    """Hashable group key for a field, using the same flattening as table output."""
+    return None if value is None else _cell_value(value)
 
 
 @mcp.tool
-def netbox_get_changelogs(filters: dict):
+def netbox_get_changelogs(filters: dict, output_format: Literal["json", "yaml"] = "json"):
     """
     Get object change records (changelogs) from NetBox based on filters.
 
     Args:
         filters: dict of filters to apply to the API call based on the NetBox API filtering options
+        output_format: "json" (default) or "yaml" to return the response as YAML text,
+                       which takes fewer tokens
 
     Returns:
         Paginated response dict with the following structure:
@@ -499,10 +1298,110 @@ def netbox_get_changelogs(filters: dict):
     - postchange_data: The object's data after the change (null for deletions)
     - time: The timestamp when the change was made
     """
-    endpoint = "core/object-changes"
+    endpoint, fallback_endpoint = _get_endpoint_info("core.objectchange")
 
     # Make API call
-    return netbox.get(endpoint, params=filters)
+    return _format_response(
+        _limit_response_size(
+            _normalize_response(
+                netbox.get(endpoint, params=filters, fallback_endpoint=fallback_endpoint)
+            )
+        ),
+        output_format,
+    )
+
+
+@mcp.tool
+def netbox_diff_change(changelog_id: int) -> dict[str, Any]:
+    """
+    Show what a single changelog entry changed, field by field.
+
+    Compares the entry's prechange_data with its postchange_data so only changed values
+    are returned. Nested objects are compared key by key (e.g. "custom_fields.owner");
+    lists are compared as a whole. last_updated is left out since every change bumps it.
+
+    Args:
+        changelog_id: ID of the core.objectchange entry (see netbox_get_changelogs)
+
+    Returns:
+        Dict with the entry's id, time, user_name, action, changed_object_type,
+        changed_object_id and object_repr, plus changes: a list of
+        {field, before, after} sorted by field (before is null for creations,
+        after is null for deletions)
+    """
+    endpoint, fallback = _get_endpoint_info("core.objectchange")
+    full_fallback = f"{fallback}/{changelog_id}" if fallback else None
+    change = _normalize_response(
+        netbox.get(f"{endpoint}/{changelog_id}", fallback_endpoint=full_fallback)
+    )
+    before = _flatten_fields(change.get("prechange_data") or {})
+    after = _flatten_fields(change.get("postchange_data") or {})
+
+    changes = [
+        {"field": field, "before": before.get(field), "after": after.get(field)}
+        for field in sorted(before.keys() | after.keys())
+        if field not in DIFF_IGNORED_FIELDS and before.get(field) != after.get(field)
+    ]
+    return {
+        "id": change.get("id"),
+        "time": change.get("time"),
+        "user_name": change.get("user_name"),
+        "action": _choice_value(change.get("action")),
+        "changed_object_type": change.get("changed_object_type"),
+        "changed_object_id": change.get("changed_object_id"),
+        "object_repr": change.get("object_repr"),
+        "changes": changes,
+    }
+
+
+def _flatten_fields(data: dict[str, Any], prefix: str = "") -> dict[str, Any]:
+    """Flatten nested dicts into dotted field paths; other values are kept as leaves."""
+    flat: dict[str, Any] = {}
+    for key, value in data.items():
+        path = f"{prefix}{key}"
+        if isinstance(value, dict) and value:
+            flat.update(_flatten_fields(value, f"{path}."))
+        else:
+            flat[path] = value
+    return flat
+
+
+@mcp.tool
+async def netbox_wait_for_job(
+    job_id: int,
+    timeout_seconds: Annotated[int, Field(default=60, ge=1, le=300)] = 60,
+    poll_interval_seconds: Annotated[float, Field(default=2.0, ge=0.5, le=30)] = 2.0,
+) -> dict[str, Any]:
+    """
+    Wait for a NetBox background job (script run, report, data source sync, ...) to finish.
+
+    Polls core.job until its status is completed, errored or failed, or until the timeout
+    passes. Polling always reads fresh data from NetBox, bypassing NETBOX_CACHE_TTL.
+
+    Args:
+        job_id: ID of the core.job entry (e.g. from a script or data source's job list)
+        timeout_seconds: How long to wait before giving up (default 60, max 300)
+        poll_interval_seconds: Delay between status checks (default 2, min 0.5)
+
+    Returns:
+        Dict with job_id, status, finished (false when the timeout passed first),
+        waited_seconds and the job itself, whose data and error fields hold the output
+    """
+    started = time.monotonic()
+    deadline = started + timeout_seconds
+    while True:
+        job = await asyncio.to_thread(netbox.get, "core/jobs", id=job_id, use_cache=False)
+        status = _choice_value(job.get("status"))
+        remaining = deadline - time.monotonic()
+        if status in JOB_FINISHED_STATUSES or remaining <= 0:
+            return {
+                "job_id": job_id,
+                "status": status,
+                "finished": status in JOB_FINISHED_STATUSES,
+                "waited_seconds": round(time.monotonic() - started, 1),
+                "job": _normalize_response(job),
+            }
+        await asyncio.sleep(min(poll_interval_seconds, remaining))
 
 
 @mcp.tool(
@@ -568,9 +1467,7 @@ def netbox_search_objects(
 
     # Validate all object types exist in mapping
     for obj_type in search_types:
-        if obj_type not in NETBOX_OBJECT_TYPES:
-            valid_types = "\n".join(f"- {t}" for t in sorted(NETBOX_OBJECT_TYPES.keys()))
-            raise ValueError(f"Invalid object_type '{obj_type}'. Must be one of:\n{valid_types}")
+        _validate_object_type(obj_type)
 
     results = {obj_type: [] for obj_type in search_types}
 
@@ -588,7 +1485,7 @@ def netbox_search_objects(
                 fallback_endpoint=fallback,
             )
             # Extract results array from paginated response
-            results[obj_type] = response.get("results", [])
+            results[obj_type] = _normalize_response(response.get("results", []))
         except Exception:  # noqa: S112 - intentional error-resilient search
             # Continue searching other types if one fails
             # results[obj_type] already has empty list
@@ -597,62 +1494,2634 @@ def netbox_search_objects(
     return results
 
 
-def _get_endpoint_info(object_type: str) -> tuple[str, str | None]:
+@mcp.tool
+def netbox_list_saved_queries() -> list[dict[str, Any]]:
     """
-    Returns (endpoint, fallback_endpoint) for the given object type.
-
-    The fallback_endpoint is used for NetBox version compatibility when
-    an endpoint path has changed between versions.
+    List the saved queries configured for this server.
 
-    Args:
-        object_type: The NetBox object type (e.g., "dcim.device")
+    Saved queries are vetted lookups maintained by the server operator. Run one by name
+    with netbox_run_saved_query, passing a value for each of its parameters.
 
     Returns:
-        Tuple of (endpoint, fallback_endpoint). fallback_endpoint is None
-        if no fallback is needed for this object type.
+        List of dicts with name, description, object_type and the parameters it takes
     """
-    type_info = NETBOX_OBJECT_TYPES[object_type]
-    return type_info["endpoint"], type_info.get("fallback_endpoint")
-
+    return [
+        {
+            "name": name,
+            "description": query.get("description", ""),
+            "object_type": query["object_type"],
+            "parameters": _saved_query_parameters(query),
+        }
+        for name, query in sorted(saved_queries.items())
+    ]
 
-def discover_plugin_types(client: NetBoxRestClient) -> dict[str, dict[str, str]]:
-    """Discover plugin object types from NetBox's object-types API.
 
-    Queries the NetBox instance for installed plugin models that have REST API
-    endpoints and returns them in the same format as NETBOX_OBJECT_TYPES.
+@mcp.tool
+def netbox_run_saved_query(
+    name: str,
+    parameters: dict[str, Any] | None = None,
+    limit: Annotated[int, Field(default=5, ge=1, le=100)] = 5,
+    offset: Annotated[int, Field(default=0, ge=0)] = 0,
+):
+    """
+    Run a saved query by name.
 
     Args:
-        client: Initialized NetBox REST API client
+        name: Saved query name, as listed by netbox_list_saved_queries
+        parameters: Values for the query's {placeholders}, e.g. {"site": "dc-east"}
+        limit: Maximum results to return (default 5, max 100)
+        offset: Results to skip for pagination (default 0)
 
     Returns:
-        Dict mapping type keys (e.g. "netbox_dns.zone") to endpoint info dicts.
-        Returns empty dict on any error (graceful degradation).
+        Paginated response dict as returned by netbox_get_objects
     """
-    logger = logging.getLogger(__name__)
-    plugin_types: dict[str, dict[str, str]] = {}
+    if name not in saved_queries:
+        available = ", ".join(sorted(saved_queries)) or "none configured"
+        raise ValueError(f"Unknown saved query '{name}'. Available: {available}")
+    query = saved_queries[name]
+
+    parameters = parameters or {}
+    missing = [p for p in _saved_query_parameters(query) if p not in parameters]
+    if missing:
+        raise ValueError(f"Saved query '{name}' requires parameters: {', '.join(missing)}")
+
+    filters = {
+        key: _fill_placeholders(value, parameters)
+        for key, value in query.get("filters", {}).items()
+    }
+    return netbox_get_objects(
+        query["object_type"],
+        filters,
+        fields=query.get("fields"),
+        limit=limit,
+        offset=offset,
+        ordering=query.get("ordering"),
+    )
 
-    try:
-        # Paginate through all object types
-        offset = 0
-        limit = 100
-        while True:
-            response = client.get(
-                "core/object-types",
-                params={"limit": limit, "offset": offset},
-                fallback_endpoint="extras/object-types",  # NetBox < 4.4
-            )
 
-            results = response.get("results", [])
-            for obj_type in results:
-                # Only include plugin models with REST API endpoints
-                if not obj_type.get("is_plugin_model", False):
-                    continue
+def _saved_query_parameters(query: dict[str, Any]) -> list[str]:
+    """Names of the {placeholders} used in a saved query's filter values, in order."""
+    names: list[str] = []
+    for value in query.get("filters", {}).values():
+        for item in value if isinstance(value, list) else [value]:
+            if isinstance(item, str):
+                names.extend(n for n in PLACEHOLDER_PATTERN.findall(item) if n not in names)
+    return names
 
-                rest_url = obj_type.get("rest_api_endpoint")
-                if not rest_url:
-                    continue
 
-                app_label = obj_type.get("app_label", "")
+def _fill_placeholders(value: Any, parameters: dict[str, Any]) -> Any:
+    """Substitute parameters into a filter value; a lone placeholder keeps the value's type."""
+    if isinstance(value, list):
+        return [_fill_placeholders(item, parameters) for item in value]
+    if not isinstance(value, str):
+        return value
+    whole = PLACEHOLDER_PATTERN.fullmatch(value)
+    if whole:
+        return parameters[whole.group(1)]
+    return PLACEHOLDER_PATTERN.sub(lambda m: str(parameters[m.group(1)]), value)
+
+
+@mcp.tool
+def netbox_reconcile_objects(
+    object_type: str,
+    expected: Annotated[list[dict[str, Any]], Field(min_length=1, max_length=200)],
+    match_on: str = "name",
+) -> dict[str, Any]:
+    """
+    Verify that objects exist in NetBox and match the intended field values.
+
+    Use after a bulk create or import, which can partially succeed, to get a
+    reconciliation report of what is missing or differs from what was intended.
+
+    Each expected object is looked up by its "id" if present, otherwise by its match_on
+    field. Only the fields given in the expected object are compared. Related objects
+    and choice fields match by ID, slug, name or value (e.g. "site": "dc1" or "site": 4);
+    lists match regardless of order; nested dicts such as custom_fields match on the
+    keys given.
+
+    Args:
+        object_type: String representing the NetBox object type (e.g. "dcim.device")
+        expected: Intended objects, as they were submitted (max 200)
+        match_on: Field used to find each object when it has no "id", and the filter
+                  of the same name (default "name"; e.g. "address" for IPs, "prefix"
+                  for prefixes, "slug", "serial")
+
+    Returns:
+        Dict with:
+            - summary: Count of objects per status
+            - results: One entry per expected object, in order, with index, status
+              (matched, mismatched, missing or ambiguous), id and, for mismatches,
+              differences (field -> {expected, actual})
+    """
+    _validate_object_type(object_type)
+
+    endpoint, fallback = _get_endpoint_info(object_type)
+    results = []
+    for index, item in enumerate(expected):
+        lookup = "id" if "id" in item else match_on
+        if lookup not in item:
+            raise ValueError(f"Expected object at index {index} has neither 'id' nor '{match_on}'")
+
+        response = netbox.get(
+            endpoint, params={lookup: item[lookup], "limit": 2}, fallback_endpoint=fallback
+        )
+        matches = response.get("results", [])
+        if not matches:
+            results.append({"index": index, "status": "missing", "id": None})
+            continue
+        if len(matches) > 1:
+            results.append({"index": index, "status": "ambiguous", "id": None})
+            continue
+
+        actual = matches[0]
+        differences = {
+            field: {"expected": value, "actual": actual.get(field)}
+            for field, value in item.items()
+            if not _values_match(value, actual.get(field))
+        }
+        result = {
+            "index": index,
+            "status": "mismatched" if differences else "matched",
+            "id": actual.get("id"),
+        }
+        if differences:
+            result["differences"] = differences
+        results.append(result)
+
+    summary = dict.fromkeys(("matched", "mismatched", "missing", "ambiguous"), 0)
+    for result in results:
+        summary[result["status"]] += 1
+
+    return {"summary": summary, "results": results}
+
+
+def _values_match(expected: Any, actual: Any) -> bool:
+    """Compare an intended field value with NetBox's representation of it."""
+    if isinstance(expected, dict) and isinstance(actual, dict):
+        return all(_values_match(value, actual.get(key)) for key, value in expected.items())
+    if isinstance(actual, dict):
+        # Related object or choice field given by its ID, slug, name or value
+        identifiers = ("id", "slug", "name", "value")
+        return any(actual.get(key) == expected for key in identifiers)
+    if isinstance(expected, list) and isinstance(actual, list):
+        return len(expected) == len(actual) and all(
+            any(_values_match(item, candidate) for candidate in actual) for item in expected
+        )
+    return expected == actual
+
+
+@mcp.tool
+def netbox_trace_cable_path(object_type: str, object_id: int) -> dict[str, Any]:
+    """
+    Trace the cable path from a port or interface to what it ultimately connects to.
+
+    Args:
+        object_type: Type of the starting object. One of dcim.interface, dcim.consoleport,
+                     dcim.consoleserverport, dcim.powerport, dcim.poweroutlet,
+                     dcim.powerfeed, dcim.frontport, dcim.rearport
+        object_id: The numeric ID of the starting object
+
+    Returns:
+        For interfaces, console and power ports: {"hops": [...]} where each hop has
+            - near_end: Terminations on this side of the cable (id, display, parent)
+            - cable: The cable between them (id, display, label), or null if unconnected
+            - far_end: Terminations on the far side of the cable (id, display, parent)
+        For front and rear ports, which can fan out: {"paths": [...]} with every
+        cable path passing through the port (origin, destination, path, is_active, is_split)
+    """
+    if object_type not in CABLE_TRACE_TYPES:
+        valid_types = "\n".join(f"- {t}" for t in sorted(CABLE_TRACE_TYPES))
+        raise ValueError(
+            f"object_type does not support cable tracing. Must be one of:\n{valid_types}"
+        )
+
+    action = CABLE_TRACE_TYPES[object_type]
+    endpoint, _ = _get_endpoint_info(object_type)
+    response = netbox.get(f"{endpoint}/{object_id}/{action}")
+
+    if action == "paths":
+        return {"paths": response}
+
+    hops = [
+        {
+            "near_end": [_summarize_termination(t) for t in near_end or []],
+            "cable": {key: cable.get(key) for key in ("id", "display", "label")} if cable else None,
+            "far_end": [_summarize_termination(t) for t in far_end or []],
+        }
+        for near_end, cable, far_end in response
+    ]
+    return {"hops": hops}
+
+
+def _summarize_termination(termination: dict[str, Any]) -> dict[str, Any]:
+    """Reduce a cable termination to its ID, name and parent object for trace output."""
+    parent = (
+        termination.get("device")
+        or termination.get("circuit")
+        or termination.get("power_panel")
+        or {}
+    )
+    return {
+        "id": termination.get("id"),
+        "display": termination.get("display"),
+        "parent": parent.get("display"),
+    }
+
+
+@mcp.tool
+def netbox_get_device_neighbors(device_id: int) -> dict[str, Any]:
+    """
+    List what a device's interfaces are connected to, as a neighbor table.
+
+    Uses the cable paths NetBox has already traced, so patch panels and circuits in
+    between are followed to the far end. When a path does not reach an endpoint
+    (e.g. it stops at an unpatched panel), the directly cabled port is shown instead
+    with path_complete false. Interfaces without a cable are left out.
+
+    Args:
+        device_id: The numeric ID of the dcim.device
+
+    Returns:
+        Dict with:
+            - neighbors: One row per connection, each with local_interface,
+                         remote_device (or circuit / power panel), remote_interface,
+                         remote_type (e.g. "dcim.interface"), cable, path_complete
+                         and reachable (whether every hop is connected and enabled)
+            - remote_devices: Sorted names of the distinct remote devices (or circuits)
+    """
+    interfaces = _get_all_objects(
+        "dcim/interfaces",
+        {
+            "device_id": device_id,
+            "cabled": "true",
+            "fields": (
+                "id,name,cable,link_peers,link_peers_type,connected_endpoints,"
+                "connected_endpoints_type,connected_endpoints_reachable"
+            ),
+        },
+    )
+
+    neighbors = []
+    for interface in interfaces:
+        path_complete = bool(interface.get("connected_endpoints"))
+        if path_complete:
+            remotes = interface["connected_endpoints"]
+            remote_type = interface.get("connected_endpoints_type")
+        else:
+            remotes = interface.get("link_peers") or []
+            remote_type = interface.get("link_peers_type")
+        for remote in remotes:
+            neighbors.append(
+                {
+                    "local_interface": interface.get("name"),
+                    "remote_device": _summarize_termination(remote)["parent"],
+                    "remote_interface": remote.get("name") or remote.get("display"),
+                    "remote_type": remote_type,
+                    "cable": (interface.get("cable") or {}).get("display"),
+                    "path_complete": path_complete,
+                    "reachable": bool(interface.get("connected_endpoints_reachable")),
+                }
+            )
+
+    remote_devices = {row["remote_device"] for row in neighbors if row["remote_device"]}
+    return {"neighbors": neighbors, "remote_devices": sorted(remote_devices)}
+
+
+@mcp.tool
+def netbox_trace_power_chain(
+    device_id: int | None = None, rack_id: int | None = None
+) -> list[dict[str, Any]]:
+    """
+    Trace power from a device's (or every device in a rack's) power ports up to the panel.
+
+    Follows each power port to the outlet or feed it is plugged into. Through a PDU,
+    the PDU's own power ports are followed in turn, up to the power feed and the power
+    panel behind it. Answers "which breaker feeds this server?" in one call.
+
+    Args:
+        device_id: The numeric ID of the dcim.device to trace
+        rack_id: Or the numeric ID of a dcim.rack to trace every device in it
+
+    Returns:
+        One entry per power port with device, power_port and paths: every chain from
+        the port upstream, each a list of hops with type ("dcim.poweroutlet" or
+        "dcim.powerfeed"), name and device (outlets) or power_panel, status, voltage,
+        amperage and phase (feeds). An empty paths list means the port is not connected.
+    """
+    if (device_id is None) == (rack_id is None):
+        raise ValueError("Provide exactly one of device_id or rack_id")
+    params: dict[str, Any] = {"fields": POWER_PORT_FIELDS}
+    if device_id is not None:
+        params["device_id"] = device_id
+    else:
+        params["rack_id"] = rack_id
+    ports = _get_all_objects("dcim/power-ports", params)
+
+    feeds: dict[int, dict[str, Any]] = {}
+    return [
+        {
+            "device": (port.get("device") or {}).get("display"),
+            "power_port": port.get("name"),
+            "paths": _power_paths(port, feeds, depth=0),
+        }
+        for port in ports
+    ]
+
+
+def _power_paths(
+    port: dict[str, Any], feeds: dict[int, dict[str, Any]], depth: int
+) -> list[list[dict[str, Any]]]:
+    """Every upstream chain from a power port, following PDUs via their own power ports."""
+    paths = []
+    endpoint_type = port.get("connected_endpoints_type")
+    for endpoint in port.get("connected_endpoints") or []:
+        if endpoint_type == "dcim.powerfeed":
+            if endpoint["id"] not in feeds:
+                feed = netbox.get("dcim/power-feeds", id=endpoint["id"])
+                feeds[endpoint["id"]] = {
+                    "type": endpoint_type,
+                    "name": feed.get("name"),
+                    "power_panel": (feed.get("power_panel") or {}).get("display"),
+                    "status": _choice_value(feed.get("status")),
+                    "voltage": feed.get("voltage"),
+                    "amperage": feed.get("amperage"),
+                    "phase": _choice_value(feed.get("phase")),
+                }
+            paths.append([feeds[endpoint["id"]]])
+            continue
+
+        hop = {
+            "type": endpoint_type,
+            "name": endpoint.get("name") or endpoint.get("display"),
+            "device": (endpoint.get("device") or {}).get("display"),
+        }
+        pdu_id = (endpoint.get("device") or {}).get("id")
+        upstream: list[list[dict[str, Any]]] = []
+        if endpoint_type == "dcim.poweroutlet" and pdu_id and depth < MAX_POWER_CHAIN_DEPTH:
+            pdu_ports = _get_all_objects(
+                "dcim/power-ports", {"device_id": pdu_id, "fields": POWER_PORT_FIELDS}
+            )
+            for pdu_port in pdu_ports:
+                upstream += _power_paths(pdu_port, feeds, depth + 1)
+        paths += [[hop, *chain] for chain in upstream] or [[hop]]
+    return paths
+
+
+@mcp.tool
+def netbox_get_circuit_summary(circuit_id: int) -> dict[str, Any]:
+    """
+    Summarize a circuit end to end: provider, both terminations and what they plug into.
+
+    Replaces separate lookups of the circuit, its terminations and their cables.
+
+    Args:
+        circuit_id: The numeric ID of the circuits.circuit
+
+    Returns:
+        Dict with cid, status, type, provider, provider_account, commit_rate (Kbps),
+        tenant, and terminations keyed by side ("A", "Z"), each with:
+            - termination_type, termination: Where the side terminates, e.g. "dcim.site"
+              and the site name, or "circuits.providernetwork" and the network
+            - port_speed, upstream_speed (Kbps), xconnect_id, pp_info
+            - cable: The cable's display name, or null when not cabled
+            - connected_to: Directly cabled ports, each with type, name and device
+    """
+    circuit = netbox.get("circuits/circuits", id=circuit_id)
+    terminations = _get_all_objects("circuits/circuit-terminations", {"circuit_id": circuit_id})
+
+    sides = {}
+    for termination in terminations:
+        # NetBox < 4.2 terminates on a site or provider network instead of a generic scope
+        termination_type = termination.get("termination_type") or (
+            "dcim.site" if termination.get("site") else "circuits.providernetwork"
+        )
+        target = (
+            termination.get("termination")
+            or termination.get("site")
+            or termination.get("provider_network")
+            or {}
+        )
+        peers_type = termination.get("link_peers_type")
+        sides[termination.get("term_side")] = {
+            "termination_type": termination_type,
+            "termination": target.get("display"),
+            "port_speed": termination.get("port_speed"),
+            "upstream_speed": termination.get("upstream_speed"),
+            "xconnect_id": termination.get("xconnect_id"),
+            "pp_info": termination.get("pp_info"),
+            "cable": (termination.get("cable") or {}).get("display"),
+            "connected_to": [
+                {
+                    "type": peers_type,
+                    "name": peer.get("name") or peer.get("display"),
+                    "device": (peer.get("device") or {}).get("display"),
+                }
+                for peer in termination.get("link_peers") or []
+            ],
+        }
+
+    return {
+        "cid": circuit.get("cid"),
+        "status": _choice_value(circuit.get("status")),
+        "type": (circuit.get("type") or {}).get("display"),
+        "provider": (circuit.get("provider") or {}).get("display"),
+        "provider_account": (circuit.get("provider_account") or {}).get("display"),
+        "commit_rate": circuit.get("commit_rate"),
+        "tenant": (circuit.get("tenant") or {}).get("display"),
+        "terminations": sides,
+    }
+
+
+@mcp.tool
+def netbox_audit_rack_cables(rack_id: int) -> dict[str, Any]:
+    """
+    List every cable terminating on a device in a rack, flagging single-ended cables.
+
+    Args:
+        rack_id: The numeric ID of the dcim.rack
+
+    Returns:
+        Dict with:
+            - cables: One entry per cable with id, label, type, status, color,
+              length, length_unit, a_terminations and b_terminations (id, display, parent)
+              and single_ended (true when either side has no termination)
+            - single_ended_count: Number of cables with only one side terminated
+    """
+    cables = []
+    for cable in _get_all_objects("dcim/cables", {"rack_id": rack_id}):
+        a_terminations = [
+            _summarize_termination(t.get("object") or {}) for t in cable.get("a_terminations") or []
+        ]
+        b_terminations = [
+            _summarize_termination(t.get("object") or {}) for t in cable.get("b_terminations") or []
+        ]
+        cables.append(
+            {
+                "id": cable.get("id"),
+                "label": cable.get("label"),
+                "type": cable.get("type"),
+                "status": _choice_value(cable.get("status")),
+                "color": cable.get("color"),
+                "length": cable.get("length"),
+                "length_unit": _choice_value(cable.get("length_unit")),
+                "a_terminations": a_terminations,
+                "b_terminations": b_terminations,
+                "single_ended": not a_terminations or not b_terminations,
+            }
+        )
+    return {
+        "cables": cables,
+        "single_ended_count": sum(1 for cable in cables if cable["single_ended"]),
+    }
+
+
+@mcp.tool
+def netbox_check_site_readiness(
+    site_id: int,
+    management_vlan_role: str = "management",
+) -> dict[str, Any]:
+    """
+    Check a site for modeling completeness during onboarding.
+
+    Each check passes when at least one matching object exists at the site:
+    - has_locations: Locations
+    - has_racks: Racks
+    - has_prefix: IP prefixes scoped to the site
+    - has_management_vlan: VLANs at the site with the management VLAN role
+    - has_contacts: Contacts assigned to the site
+    - has_circuits: Circuits terminating at the site
+
+    Args:
+        site_id: The numeric ID of the dcim.site
+        management_vlan_role: Slug of the ipam.role that marks management VLANs
+                              (default "management")
+
+    Returns:
+        Dict with:
+            - site: The site's display name
+            - ready: Whether every check passed
+            - checks: One entry per check with check, passed and count
+    """
+    site = netbox.get("dcim/sites", id=site_id)
+
+    queries = {
+        "has_locations": ("dcim/locations", {"site_id": site_id}),
+        "has_racks": ("dcim/racks", {"site_id": site_id}),
+        "has_prefix": ("ipam/prefixes", {"site_id": site_id}),
+        "has_management_vlan": (
+            "ipam/vlans",
+            {"site_id": site_id, "role": management_vlan_role},
+        ),
+        "has_contacts": (
+            "tenancy/contact-assignments",
+            {"object_type": "dcim.site", "object_id": site_id},
+        ),
+        "has_circuits": ("circuits/circuits", {"site_id": site_id}),
+    }
+    checks = []
+    for check, (endpoint, params) in queries.items():
+        count = netbox.get(endpoint, params={**params, "limit": 1, "fields": "id"})["count"]
+        checks.append({"check": check, "passed": count > 0, "count": count})
+
+    return {
+        "site": site.get("display"),
+        "ready": all(check["passed"] for check in checks),
+        "checks": checks,
+    }
+
+
+@mcp.tool
+def netbox_find_contacts_for_object(
+    object_type: str, object_id: int, include_related: bool = True
+) -> list[dict[str, Any]]:
+    """
+    Find the contacts assigned to an object, with their roles, emails and phone numbers.
+
+    Answers "who do I call about circuit X" in one call. With include_related, contacts
+    of the object's site (devices, racks, VMs) or provider (circuits) are included too,
+    since contacts are often assigned there rather than on each object.
+
+    Args:
+        object_type: NetBox object type, e.g. "dcim.site", "dcim.device", "circuits.circuit"
+        object_id: The numeric ID of the object
+        include_related: Also include contacts of the site or provider (default true)
+
+    Returns:
+        Contacts ordered by priority (primary first), each with name, title, email, phone,
+        role, priority and via (the object type the contact is assigned to)
+    """
+    _validate_object_type(object_type)
+
+    sources = [(object_type, object_id)]
+    if include_related and object_type in RELATED_CONTACT_SOURCES:
+        field, related_type = RELATED_CONTACT_SOURCES[object_type]
+        endpoint, _ = _get_endpoint_info(object_type)
+        related = netbox.get(endpoint, id=object_id, params={"fields": f"id,{field}"}).get(field)
+        if related:
+            sources.append((related_type, related["id"]))
+
+    assignments: list[tuple[str, dict[str, Any]]] = []
+    for source_type, source_id in sources:
+        params = {"object_type": source_type, "object_id": source_id}
+        for assignment in _get_all_objects("tenancy/contact-assignments", params):
+            assignments.append((source_type, assignment))
+
+    # Assignments only carry a brief contact; fetch email and phone in one query
+    contacts: dict[int, dict[str, Any]] = {}
+    contact_ids = sorted({assignment["contact"]["id"] for _, assignment in assignments})
+    if contact_ids:
+        contact_params = {"id": contact_ids, "fields": "id,name,title,email,phone"}
+        for contact in _get_all_objects("tenancy/contacts", contact_params):
+            contacts[contact["id"]] = contact
+
+    results = []
+    for source_type, assignment in assignments:
+        contact = contacts.get(assignment["contact"]["id"], assignment["contact"])
+        results.append(
+            {
+                "name": contact.get("name"),
+                "title": contact.get("title"),
+                "email": contact.get("email"),
+                "phone": contact.get("phone"),
+                "role": (assignment.get("role") or {}).get("display"),
+                "priority": _choice_value(assignment.get("priority")),
+                "via": source_type,
+            }
+        )
+    results.sort(key=lambda c: (CONTACT_PRIORITY_ORDER.get(c["priority"], 3), c["name"] or ""))
+    return results
+
+
+@mcp.tool
+def netbox_compare_site_layouts(site_id: int, reference_site_id: int) -> dict[str, Any]:
+    """
+    Compare a site's prefix and VLAN layout against a reference (golden template) site.
+
+    Structure, not addresses, is compared: prefixes are grouped by role and compared by
+    address family and prefix length; VLANs available at each site (including those in
+    VLAN groups) are matched by VID, name and group, falling back to VID and name (e.g.
+    across per-site groups), and compared by name and role. Use this to make a new site
+    look like the reference site.
+
+    Args:
+        site_id: The numeric ID of the dcim.site to check
+        reference_site_id: The numeric ID of the dcim.site to compare against
+
+    Returns:
+        Dict with:
+            - site / reference_site: The sites' display names
+            - matches: Whether no structural differences were found
+            - prefixes: Per-role entries (only roles that differ) with the prefix lengths
+              missing from and extra to the site, e.g. "IPv4 /24"
+            - vlans: missing (in the reference only) and extra (at the site only), each
+              with vid, name and group,
+              mismatched (same VID, different role, or renamed when the VID has one VLAN
+              missing and one extra) and collisions (per site, VIDs used by more than one
+              VLAN, e.g. in different groups; informational, not counted against matches)
+    """
+    site = netbox.get("dcim/sites", id=site_id)
+    reference = netbox.get("dcim/sites", id=reference_site_id)
+
+    prefix_shapes = {
+        sid: _prefix_shapes_by_role(
+            _get_all_objects("ipam/prefixes", {"site_id": sid, "fields": "prefix,role"})
+        )
+        for sid in (site_id, reference_site_id)
+    }
+    prefix_diffs = []
+    roles = prefix_shapes[site_id].keys() | prefix_shapes[reference_site_id].keys()
+    for role in sorted(roles, key=lambda r: r or ""):
+        actual = prefix_shapes[site_id].get(role, Counter())
+        expected = prefix_shapes[reference_site_id].get(role, Counter())
+        if actual != expected:
+            prefix_diffs.append(
+                {
+                    "role": role,
+                    "missing": sorted((expected - actual).elements()),
+                    "extra": sorted((actual - expected).elements()),
+                }
+            )
+
+    # available_at_site also covers VLANs in groups scoped to the site or its region
+    vlans = {
+        sid: _get_all_objects(
+            "ipam/vlans", {"available_at_site": sid, "fields": "vid,name,role,group"}
+        )
+        for sid in (site_id, reference_site_id)
+    }
+    vlan_diff = _compare_vlans(vlans[site_id], vlans[reference_site_id])
+    vlan_diff["collisions"] = {
+        "site": _vid_collisions(vlans[site_id]),
+        "reference_site": _vid_collisions(vlans[reference_site_id]),
+    }
+
+    return {
+        "site": site.get("display"),
+        "reference_site": reference.get("display"),
+        "matches": not (
+            prefix_diffs or vlan_diff["missing"] or vlan_diff["extra"] or vlan_diff["mismatched"]
+        ),
+        "prefixes": prefix_diffs,
+        "vlans": vlan_diff,
+    }
+
+
+def _compare_vlans(
+    actual: list[dict[str, Any]], expected: list[dict[str, Any]]
+) -> dict[str, list[dict[str, Any]]]:
+    """
+    Diff two sites' VLANs, keyed by (VID, name, group) so that none are collapsed.
+
+    VLANs without an exact match are paired by VID and name first (e.g. the same VLAN in
+    each site's own group), then by VID when one is left on each side (a rename). Paired
+    VLANs are mismatched when their names or roles differ.
+    """
+    actual_by_key = {_vlan_key(vlan): vlan for vlan in actual}
+    expected_by_key = {_vlan_key(vlan): vlan for vlan in expected}
+    shared = actual_by_key.keys() & expected_by_key.keys()
+    pairs = [(actual_by_key[key], expected_by_key[key]) for key in shared]
+
+    unmatched: dict[tuple[int, str], list[dict[str, Any]]] = {}
+    for key in sorted(expected_by_key.keys() - shared):
+        unmatched.setdefault(key[:2], []).append(expected_by_key[key])
+    extra = []
+    for key in sorted(actual_by_key.keys() - shared):
+        candidates = unmatched.get(key[:2])
+        if candidates:
+            pairs.append((actual_by_key[key], candidates.pop(0)))
+        else:
+            extra.append(actual_by_key[key])
+    missing = [vlan for vlans in unmatched.values() for vlan in vlans]
+
+    missing_vids = Counter(vlan["vid"] for vlan in missing)
+    extra_vids = Counter(vlan["vid"] for vlan in extra)
+    renamed = {vid for vid, count in missing_vids.items() if count == 1 and extra_vids[vid] == 1}
+    renamed_from = {vlan["vid"]: vlan for vlan in missing if vlan["vid"] in renamed}
+    pairs += [(vlan, renamed_from[vlan["vid"]]) for vlan in extra if vlan["vid"] in renamed]
+
+    mismatched = []
+    for actual_vlan, expected_vlan in sorted(pairs, key=lambda pair: _vlan_key(pair[1])):
+        actual_view = (actual_vlan.get("name"), _role_slug(actual_vlan))
+        expected_view = (expected_vlan.get("name"), _role_slug(expected_vlan))
+        if actual_view != expected_view:
+            mismatched.append(
+                {
+                    "vid": expected_vlan["vid"],
+                    "name": actual_view[0],
+                    "role": actual_view[1],
+                    "reference_name": expected_view[0],
+                    "reference_role": expected_view[1],
+                }
+            )
+
+    return {
+        "missing": [_vlan_summary(vlan) for vlan in missing if vlan["vid"] not in renamed],
+        "extra": [_vlan_summary(vlan) for vlan in extra if vlan["vid"] not in renamed],
+        "mismatched": mismatched,
+    }
+
+
+def _vlan_key(vlan: dict[str, Any]) -> tuple[int, str, str]:
+    """Identify a VLAN within a site: the same VID and name may exist in several groups."""
+    return (vlan["vid"], vlan.get("name") or "", (vlan.get("group") or {}).get("name") or "")
+
+
+def _vlan_summary(vlan: dict[str, Any]) -> dict[str, Any]:
+    """The VID, name and group name of a VLAN, for comparison results."""
+    return {
+        "vid": vlan["vid"],
+        "name": vlan.get("name"),
+        "group": (vlan.get("group") or {}).get("name"),
+    }
+
+
+def _vid_collisions(vlans: list[dict[str, Any]]) -> list[dict[str, Any]]:
+    """VIDs used by more than one VLAN at a site, e.g. in different VLAN groups."""
+    by_vid: dict[int, list[dict[str, Any]]] = {}
+    for vlan in vlans:
+        by_vid.setdefault(vlan["vid"], []).append(vlan)
+    return [
+        {
+            "vid": vid,
+            "vlans": [
+                {"name": vlan.get("name"), "group": (vlan.get("group") or {}).get("name")}
+                for vlan in same_vid
+            ],
+        }
+        for vid, same_vid in sorted(by_vid.items())
+        if len(same_vid) > 1
+    ]
+
+
+def _prefix_shapes_by_role(prefixes: list[dict[str, Any]]) -> dict[str | None, Counter]:
+    """Count prefixes per role by family and length, e.g. {"mgmt": {"IPv4 /24": 2}}."""
+    shapes: dict[str | None, Counter] = {}
+    for prefix in prefixes:
+        network = ipaddress.ip_network(prefix["prefix"], strict=False)
+        shape = f"IPv{network.version} /{network.prefixlen}"
+        shapes.setdefault(_role_slug(prefix), Counter())[shape] += 1
+    return shapes
+
+
+def _role_slug(obj: dict[str, Any]) -> str | None:
+    """The slug of an object's ipam.role, or None when it has no role."""
+    role = obj.get("role")
+    return role.get("slug") if isinstance(role, dict) else None
+
+
+@mcp.tool
+def netbox_get_rack_elevation(
+    rack_id: int,
+    face: Literal["front", "rear"] | None = None,
+    include_svg_url: bool = False,
+) -> dict[str, Any]:
+    """
+    Get the unit-by-unit occupancy of a rack, as NetBox draws it in the rack elevation.
+
+    Use this instead of reconstructing elevations from device position and u_height.
+
+    Args:
+        rack_id: The numeric ID of the dcim.rack
+        face: "front" or "rear" to return one face only (default: both faces)
+        include_svg_url: Also return the URL of NetBox's SVG rendering for each face.
+                         The URL requires the same API token to open.
+
+    Returns:
+        Dict keyed by face ("front", "rear"), each an ordered list of units (top to bottom):
+            - unit: Unit name (e.g. "U42")
+            - occupied: Whether the unit is occupied
+            - device: Display name of the device in the unit, or null
+        With include_svg_url, also "svg_urls": {face: url}
+    """
+    faces = [face] if face else ["front", "rear"]
+    endpoint = f"dcim/racks/{rack_id}/elevation"
+
+    result: dict[str, Any] = {}
+    for rack_face in faces:
+        units = _get_all_objects(endpoint, {"face": rack_face})
+        result[rack_face] = [
+            {
+                "unit": unit.get("name"),
+                "occupied": unit.get("occupied"),
+                "device": (unit.get("device") or {}).get("display"),
+            }
+            for unit in units
+        ]
+
+    if include_svg_url:
+        result["svg_urls"] = {
+            rack_face: f"{netbox.api_url}/{endpoint}/?face={rack_face}&render=svg"
+            for rack_face in faces
+        }
+
+    return result
+
+
+@mcp.tool
+def netbox_find_rack_space(
+    u_height: Annotated[int, Field(ge=1, le=100)],
+    site_id: int | None = None,
+    rack_id: int | None = None,
+    face: Literal["front", "rear"] = "front",
+    full_depth: bool = True,
+) -> list[dict[str, Any]]:
+    """
+    Find racks with a contiguous free span of at least u_height units.
+
+    Uses NetBox's rack elevation, so half-depth devices on the other face and
+    multi-unit devices are accounted for, and skips units held by rack reservations.
+    Each rack costs one or two elevation queries, so prefer rack_id on large sites.
+
+    Args:
+        u_height: Height in units of the equipment to place
+        site_id: Search every rack in this site
+        rack_id: Or search a single rack
+        face: Face the equipment mounts on (default "front")
+        full_depth: Whether the equipment is full depth, requiring the units to be
+                    free on both faces (default true, as for most servers)
+
+    Returns:
+        Racks with room, largest free span first, each with rack_id, rack,
+        largest_free_span, and positions: every valid position (the lowest unit the
+        equipment would occupy, as NetBox's device position field expects)
+    """
+    if (site_id is None) == (rack_id is None):
+        raise ValueError("Provide exactly one of site_id or rack_id")
+    scope = {"site_id": site_id} if site_id is not None else {"id": rack_id}
+    racks = _get_all_objects("dcim/racks", {**scope, "fields": "id,display"})
+    reservation_scope = {"site_id": site_id} if site_id is not None else {"rack_id": rack_id}
+    reserved: dict[int, set[float]] = {}
+    for reservation in _get_all_objects(
+        "dcim/rack-reservations", {**reservation_scope, "fields": "rack,units"}
+    ):
+        reserved.setdefault(reservation["rack"]["id"], set()).update(reservation["units"])
+
+    faces = ["front", "rear"] if full_depth else [face]
+    candidates = []
+    for rack in racks:
+        free: set[float] | None = None
+        for rack_face in faces:
+            units = _get_all_objects(f"dcim/racks/{rack['id']}/elevation", {"face": rack_face})
+            face_free = {unit["id"] for unit in units if not unit.get("occupied")}
+            free = face_free if free is None else free & face_free
+        free = (free or set()) - reserved.get(rack["id"], set())
+
+        positions = sorted(
+            unit for unit in free if all(unit + offset in free for offset in range(u_height))
+        )
+        if positions:
+            candidates.append(
+                {
+                    "rack_id": rack["id"],
+                    "rack": rack.get("display"),
+                    "largest_free_span": _largest_span(free),
+                    "positions": positions,
+                }
+            )
+
+    candidates.sort(key=lambda candidate: candidate["largest_free_span"], reverse=True)
+    return candidates
+
+
+def _largest_span(units: set[float]) -> int:
+    """Length of the longest run of consecutive unit numbers."""
+    longest = 0
+    for unit in units:
+        if unit - 1 in units:
+            continue  # Not the bottom of a run
+        length = 1
+        while unit + length in units:
+            length += 1
+        longest = max(longest, length)
+    return longest
+
+
+@mcp.tool
+def netbox_get_config_context(
+    object_type: Literal["dcim.device", "virtualization.virtualmachine"],
+    object_id: int,
+) -> dict[str, Any]:
+    """
+    Get the rendered config context of a device or virtual machine.
+
+    The config context is the intended configuration data NetBox merges from all
+    matching config contexts plus the object's own local context data. It is
+    large and usually left out by field filtering, so ask for it here explicitly.
+
+    Args:
+        object_type: "dcim.device" or "virtualization.virtualmachine"
+        object_id: The numeric ID of the device or virtual machine
+
+    Returns:
+        Dict with:
+            - id, name: The object's identity
+            - config_context: Final merged context, with local context data applied last
+            - local_context_data: The object's own local context data, or null
+    """
+    endpoint, _ = _get_endpoint_info(object_type)
+    return netbox.get(
+        f"{endpoint}/{object_id}",
+        params={"fields": "id,name,config_context,local_context_data"},
+    )
+
+
+@mcp.tool
+def netbox_get_custom_fields(object_type: str) -> list[dict[str, Any]]:
+    """
+    List the custom fields defined for an object type, with their data types and choices.
+
+    Use this before filtering on custom fields (cf_<name>=value) or interpreting the
+    custom_fields values of objects, especially when an organization relies on them.
+
+    Args:
+        object_type: NetBox object type, e.g. "dcim.device" or "ipam.prefix"
+
+    Returns:
+        Custom fields in display order, each with name, label, type (e.g. "text",
+        "integer", "select", "object"), required, default, description, group_name,
+        filter_logic, related_object_type (object fields), validation (minimum, maximum,
+        regex), choices (values of a select field's choice set) and base_choices
+        (the predefined set a choice set extends, e.g. "ISO_3166", or null)
+    """
+    _validate_object_type(object_type)
+
+    fields = _get_all_objects(
+        "extras/custom-fields", {"object_type": object_type, "ordering": "weight,name"}
+    )
+    choice_set_ids = sorted({f["choice_set"]["id"] for f in fields if f.get("choice_set")})
+    choice_sets: dict[int, dict[str, Any]] = {}
+    if choice_set_ids:
+        params = {"id": choice_set_ids, "fields": "id,base_choices,extra_choices"}
+        for choice_set in _get_all_objects("extras/custom-field-choice-sets", params):
+            choice_sets[choice_set["id"]] = choice_set
+
+    results = []
+    for field in fields:
+        choice_set = choice_sets.get((field.get("choice_set") or {}).get("id"), {})
+        results.append(
+            {
+                "name": field.get("name"),
+                "label": field.get("label"),
+                "type": _choice_value(field.get("type")),
+                "required": field.get("required"),
+                "default": field.get("default"),
+                "description": field.get("description"),
+                "group_name": field.get("group_name"),
+                "filter_logic": _choice_value(field.get("filter_logic")),
+                "related_object_type": field.get("related_object_type"),
+                "validation": {
+                    "minimum": field.get("validation_minimum"),
+                    "maximum": field.get("validation_maximum"),
+                    "regex": field.get("validation_regex"),
+                },
+                "choices": [value for value, _ in choice_set.get("extra_choices") or []],
+                "base_choices": _choice_value(choice_set.get("base_choices")),
+            }
+        )
+    return results
+
+
+@mcp.tool
+def netbox_get_device_interface_summary(device_id: int) -> dict[str, Any]:
+    """
+    Summarize a device's interfaces by type, speed and duplex, with LAG membership.
+
+    Answers questions like "does this switch have free 10G ports" in one call.
+    An interface counts as free when it is an enabled physical port (not virtual,
+    bridge or LAG) with no cable attached and not marked as connected. For
+    utilization, every interface is counted once as disabled, connected (cabled or
+    marked connected) or unconnected.
+
+    Args:
+        device_id: The numeric ID of the dcim.device
+
+    Returns:
+        Dict with:
+            - total: Number of interfaces on the device
+            - utilization: {"connected": n, "unconnected": n, "disabled": n}
+            - by_type: {type: {"total": n, "free": n}} keyed by NetBox interface type
+                       (e.g. "10gbase-x-sfpp", "1000base-t")
+            - free_ports: {type: [free interface names]}
+            - by_speed: {speed_kbps: n}, "unset" for interfaces without a speed
+            - by_duplex: {duplex: n}, "unset" for interfaces without a duplex
+            - lags: {lag_name: [member interface names]}
+    """
+    interfaces = _get_all_objects(
+        "dcim/interfaces",
+        {"device_id": device_id, "fields": INTERFACE_SUMMARY_FIELDS},
+    )
+
+    utilization = {"connected": 0, "unconnected": 0, "disabled": 0}
+    by_type: dict[str, dict[str, int]] = {}
+    free_ports: dict[str, list[str]] = {}
+    lags: dict[str, list[str]] = {}
+    for interface in interfaces:
+        if not interface.get("enabled"):
+            utilization["disabled"] += 1
+        elif interface.get("cable") or interface.get("mark_connected"):
+            utilization["connected"] += 1
+        else:
+            utilization["unconnected"] += 1
+
+        interface_type = _choice_value(interface.get("type")) or "unset"
+        counts = by_type.setdefault(interface_type, {"total": 0, "free": 0})
+        counts["total"] += 1
+        if _is_free_interface(interface):
+            counts["free"] += 1
+            free_ports.setdefault(interface_type, []).append(interface.get("name"))
+        if interface.get("lag"):
+            lags.setdefault(interface["lag"].get("name"), []).append(interface.get("name"))
+
+    by_speed = Counter(str(interface.get("speed") or "unset") for interface in interfaces)
+    by_duplex = Counter(
+        _choice_value(interface.get("duplex")) or "unset" for interface in interfaces
+    )
+
+    return {
+        "total": len(interfaces),
+        "utilization": utilization,
+        "by_type": by_type,
+        "free_ports": free_ports,
+        "by_speed": dict(by_speed),
+        "by_duplex": dict(by_duplex),
+        "lags": lags,
+    }
+
+
+@mcp.tool
+def netbox_find_free_ports(
+    site_id: int,
+    interface_type: str,
+    min_free: Annotated[int, Field(default=1, ge=1)] = 1,
+    role_id: int | None = None,
+) -> list[dict[str, Any]]:
+    """
+    Find devices in a site with at least min_free free interfaces of a given type.
+
+    An interface counts as free when it is an enabled physical port with no cable
+    attached and not marked as connected.
+
+    Args:
+        site_id: The numeric ID of the dcim.site to search
+        interface_type: NetBox interface type value (e.g. "10gbase-x-sfpp", "1000base-t")
+        min_free: Minimum number of free interfaces a device needs to be listed (default 1)
+        role_id: Optional device role ID to restrict candidates (e.g. only access switches)
+
+    Returns:
+        Candidate devices, most free interfaces first, each with:
+            - device_id, device: The device's ID and display name
+            - free_count: Number of free interfaces of the requested type
+            - free_interfaces: Names of those interfaces
+    """
+    interfaces = _get_all_objects(
+        "dcim/interfaces",
+        {
+            "site_id": site_id,
+            "type": interface_type,
+            "enabled": True,
+            "cabled": False,
+            "fields": f"{INTERFACE_SUMMARY_FIELDS},device",
+        },
+    )
+
+    role_device_ids = None
+    if role_id is not None:
+        devices = _get_all_objects(
+            "dcim/devices", {"site_id": site_id, "role_id": role_id, "fields": "id"}
+        )
+        role_device_ids = {device["id"] for device in devices}
+
+    candidates: dict[int, dict[str, Any]] = {}
+    for interface in interfaces:
+        device = interface.get("device") or {}
+        if role_device_ids is not None and device.get("id") not in role_device_ids:
+            continue
+        if not _is_free_interface(interface):
+            continue
+        candidate = candidates.setdefault(
+            device.get("id"),
+            {"device_id": device.get("id"), "device": device.get("display"), "free_interfaces": []},
+        )
+        candidate["free_interfaces"].append(interface.get("name"))
+
+    results = [
+        {**candidate, "free_count": len(candidate["free_interfaces"])}
+        for candidate in candidates.values()
+        if len(candidate["free_interfaces"]) >= min_free
+    ]
+    results.sort(key=lambda candidate: candidate["free_count"], reverse=True)
+    return results
+
+
+@mcp.tool
+def netbox_find_module_types_by_attributes(
+    profile_id: int,
+    attributes: dict[str, Any] | None = None,
+) -> dict[str, Any]:
+    """
+    Get a module type profile's attribute schema and the module types matching attribute values.
+
+    Module type profiles (NetBox 4.3+) define a JSON schema of attributes, such as
+    a PSU's wattage or an optic's reach, that each module type of that profile fills in.
+
+    Args:
+        profile_id: The numeric ID of the dcim.moduletypeprofile
+        attributes: Optional attribute values to match exactly, keyed by attribute name
+                    from the profile schema (e.g. {"wattage": 750})
+
+    Returns:
+        Dict with:
+            - profile: id, name, description, attributes (name -> schema definition)
+              and required (required attribute names)
+            - module_types: Matching module types (id, display, manufacturer, model,
+              part_number, attributes)
+
+    Raises:
+        ValueError: If an attribute name is not defined in the profile schema
+    """
+    profile = netbox.get("dcim/module-type-profiles", id=profile_id)
+    schema = profile.get("schema") or {}
+    properties = schema.get("properties") or {}
+
+    attributes = attributes or {}
+    unknown = sorted(set(attributes) - set(properties))
+    if unknown:
+        valid = ", ".join(sorted(properties)) or "(none)"
+        raise ValueError(
+            f"Unknown attribute(s) for profile {profile.get('name')!r}: {', '.join(unknown)}. "
+            f"Valid attributes: {valid}"
+        )
+
+    params: dict[str, Any] = {"profile_id": profile_id}
+    params.update({f"attr_{name}": value for name, value in attributes.items()})
+    module_types = _get_all_objects("dcim/module-types", params)
+
+    return {
+        "profile": {
+            "id": profile.get("id"),
+            "name": profile.get("name"),
+            "description": profile.get("description"),
+            "attributes": properties,
+            "required": schema.get("required", []),
+        },
+        "module_types": [
+            {
+                "id": module_type.get("id"),
+                "display": module_type.get("display"),
+                "manufacturer": (module_type.get("manufacturer") or {}).get("display"),
+                "model": module_type.get("model"),
+                "part_number": module_type.get("part_number"),
+                "attributes": module_type.get("attributes"),
+            }
+            for module_type in module_types
+        ],
+    }
+
+
+@mcp.tool
+def netbox_get_available_ips(
+    prefix_id: int,
+    limit: Annotated[int, Field(default=10, ge=1, le=100)] = 10,
+) -> list[dict[str, Any]]:
+    """
+    List the next available IP addresses in a prefix.
+
+    This only reports free addresses; nothing is allocated or reserved.
+
+    Args:
+        prefix_id: The numeric ID of the ipam.prefix to look in
+        limit: Maximum number of available addresses to return (default 10, max 100)
+
+    Returns:
+        List of available addresses in ascending order, each with family, address and vrf.
+        An empty list means the prefix is full.
+    """
+    return netbox.get(f"ipam/prefixes/{prefix_id}/available-ips", params={"limit": limit})
+
+
+@mcp.tool
+def netbox_get_available_prefixes(
+    prefix_id: int,
+    prefix_length: Annotated[int | None, Field(default=None, ge=0, le=128)] = None,
+) -> list[dict[str, Any]]:
+    """
+    List the unallocated child blocks of a prefix.
+
+    This only reports free space; nothing is allocated or reserved.
+
+    Args:
+        prefix_id: The numeric ID of the parent ipam.prefix
+        prefix_length: Optional child prefix length you want to carve out (e.g. 26).
+                       When set, only free blocks large enough to hold a child of
+                       this length are returned.
+
+    Returns:
+        List of free blocks, each with family, prefix and vrf.
+        An empty list means no suitable free space remains.
+    """
+    available = netbox.get(f"ipam/prefixes/{prefix_id}/available-prefixes")
+    if prefix_length is None:
+        return available
+    fitting = []
+    for block in available:
+        network = ipaddress.ip_network(block["prefix"])
+        if network.prefixlen <= prefix_length <= network.max_prefixlen:
+            fitting.append(block)
+    return fitting
+
+
+@mcp.tool
+def netbox_get_available_vlans(
+    vlan_group_id: int,
+    limit: Annotated[int, Field(default=10, ge=1, le=100)] = 10,
+) -> list[dict[str, Any]]:
+    """
+    List the next available VLAN IDs in a VLAN group.
+
+    This only reports free VLAN IDs; nothing is allocated or reserved.
+
+    Args:
+        vlan_group_id: The numeric ID of the ipam.vlangroup to look in
+        limit: Maximum number of available VLAN IDs to return (default 10, max 100)
+
+    Returns:
+        List of available VLANs in ascending order, each with vid and group.
+        An empty list means the group's VLAN ID ranges are exhausted.
+    """
+    return netbox.get(f"ipam/vlan-groups/{vlan_group_id}/available-vlans", params={"limit": limit})
+
+
+@mcp.tool
+def netbox_forecast_prefix_capacity(
+    role_id: int,
+    site_id: int | None = None,
+    months: Annotated[int, Field(default=6, ge=1, le=36)] = 6,
+) -> list[dict[str, Any]]:
+    """
+    Project when prefixes of a role will run out of IP addresses.
+
+    Current use is the number of IP addresses in each prefix. Growth is the net number
+    of IP addresses created minus deleted in the prefix over the last N months,
+    taken from the changelog, and is assumed to continue linearly.
+
+    Args:
+        role_id: The numeric ID of the ipam.role whose prefixes to forecast
+        site_id: Optional site ID to restrict the forecast to one site
+        months: How many months of changelog history to derive growth from (default 6, max 36)
+
+    Returns:
+        One entry per prefix, soonest exhaustion first (no projection last), each with:
+            - id, prefix, vrf: The prefix
+            - size: Usable addresses (as NetBox counts them for utilization)
+            - used, utilization: IP addresses in the prefix and percent of size used
+            - net_growth, growth_per_month: Net IPs added over the window and per month
+            - months_to_exhaustion, projected_exhaustion_date: The projection, or null
+              when the prefix is not growing (the date is also null past year 9999)
+    """
+    params: dict[str, Any] = {"role_id": role_id, "fields": "id,prefix,vrf,is_pool"}
+    if site_id is not None:
+        params["site_id"] = site_id
+    prefixes = _get_all_objects("ipam/prefixes", params)
+
+    today = datetime.date.today()
+    window_start = today - datetime.timedelta(days=months * 30)
+    endpoint, fallback = _get_endpoint_info("core.objectchange")
+    changes = _get_all_objects(
+        endpoint,
+        {
+            "changed_object_type": "ipam.ipaddress",
+            "action": ["create", "delete"],
+            "time_after": window_start.isoformat(),
+            "fields": "action,prechange_data,postchange_data",
+        },
+        fallback_endpoint=fallback,
+    )
+
+    # Bucket the changes once by (VRF, containing network) for every prefix length in play,
+    # so each prefix's growth is a lookup rather than a scan of the whole changelog.
+    networks = [ipaddress.ip_network(prefix["prefix"]) for prefix in prefixes]
+    lengths = {(network.version, network.prefixlen) for network in networks}
+    growth: Counter[tuple[int | None, Any]] = Counter()
+    for change in changes:
+        data = change.get("postchange_data") or change.get("prechange_data") or {}
+        if not data.get("address"):
+            continue
+        ip = ipaddress.ip_interface(data["address"]).ip
+        delta = 1 if _choice_value(change.get("action")) == "create" else -1
+        for version, length in lengths:
+            if ip.version == version:
+                growth[(data.get("vrf"), ipaddress.ip_network((ip, length), strict=False))] += delta
+
+    forecasts = []
+    for prefix, network in zip(prefixes, networks, strict=True):
+        vrf_id = (prefix.get("vrf") or {}).get("id")
+        used = netbox.get(
+            "ipam/ip-addresses",
+            params={"parent": prefix["prefix"], "vrf_id": vrf_id or "null", "limit": 1},
+        )["count"]
+        net_growth = growth[(vrf_id, network)]
+
+        size = _usable_address_count(network, prefix.get("is_pool", False))
+        growth_per_month = net_growth / months
+        months_to_exhaustion = None
+        exhaustion_date = None
+        if growth_per_month > 0:
+            months_to_exhaustion = round(max(size - used, 0) / growth_per_month, 1)
+            days_left = round(months_to_exhaustion * 30)
+            try:
+                exhaustion_date = (today + datetime.timedelta(days=days_left)).isoformat()
+            except OverflowError:
+                pass  # Past year 9999, e.g. a slowly growing IPv6 prefix
+
+        forecasts.append(
+            {
+                "id": prefix["id"],
+                "prefix": prefix["prefix"],
+                "vrf": (prefix.get("vrf") or {}).get("display"),
+                "size": size,
+                "used": used,
+                "utilization": round(used / size * 100, 1) if size else None,
+                "net_growth": net_growth,
+                "growth_per_month": round(growth_per_month, 2),
+                "months_to_exhaustion": months_to_exhaustion,
+                "projected_exhaustion_date": exhaustion_date,
+            }
+        )
+
+    forecasts.sort(
+        key=lambda f: (f["months_to_exhaustion"] is None, f["months_to_exhaustion"] or 0)
+    )
+    return forecasts
+
+
+@mcp.tool
+def netbox_get_prefix_utilization(
+    prefix_ids: list[int] | None = None,
+    within: str | None = None,
+    prefix_length: Annotated[int | None, Field(default=None, ge=0, le=128)] = None,
+    min_utilization: Annotated[float, Field(default=0, ge=0, le=100)] = 0,
+    limit: Annotated[int, Field(default=50, ge=1, le=500)] = 50,
+) -> list[dict[str, Any]]:
+    """
+    Get utilization for prefixes, with their child prefix and IP address counts.
+
+    Utilization follows NetBox's own rules: container prefixes count the address space
+    covered by child prefixes; other prefixes count IP addresses against usable
+    addresses; prefixes marked utilized are 100%. Answers questions like "which /24s in
+    10.0.0.0/16 are over 80% used" in one call.
+
+    Args:
+        prefix_ids: IDs of the ipam.prefix objects to report on
+        within: Or a CIDR (e.g. an aggregate, "10.0.0.0/16") whose child prefixes to report on
+        prefix_length: Only prefixes of this length, e.g. 24 (with within)
+        min_utilization: Only return prefixes at or above this percentage (default 0)
+        limit: Maximum number of prefixes to return (default 50, max 500). Every matching
+               prefix is evaluated first, so the most utilized ones are never cut off.
+
+    Returns:
+        Prefixes sorted by utilization (highest first), each with id, prefix, vrf, status,
+        size, child_prefixes, ip_addresses and utilization (percent)
+    """
+    if not prefix_ids and not within:
+        raise ValueError("Provide prefix_ids or within")
+    params: dict[str, Any] = {"fields": "id,prefix,vrf,status,is_pool,mark_utilized"}
+    if prefix_ids:
+        params["id"] = prefix_ids
+    if within:
+        params["within"] = within
+    if prefix_length is not None:
+        params["mask_length"] = prefix_length
+    prefixes = _get_all_objects("ipam/prefixes", params)
+
+    report = [_prefix_utilization(prefix) for prefix in prefixes]
+    report = [entry for entry in report if entry["utilization"] >= min_utilization]
+    report.sort(key=lambda entry: entry["utilization"], reverse=True)
+    return report[:limit]
+
+
+def _prefix_utilization(prefix: dict[str, Any]) -> dict[str, Any]:
+    """Compute one prefix's utilization the way NetBox does, plus child counts."""
+    network = ipaddress.ip_network(prefix["prefix"])
+    vrf_id = (prefix.get("vrf") or {}).get("id")
+    is_container = _choice_value(prefix.get("status")) == "container"
+    # NetBox treats a global (no VRF) container as the parent of children in every VRF
+    scope = {} if is_container and vrf_id is None else {"vrf_id": vrf_id or "null"}
+
+    ip_count = netbox.get(
+        "ipam/ip-addresses", params={"parent": prefix["prefix"], **scope, "limit": 1}
+    )["count"]
+    if is_container:
+        children = _get_all_objects(
+            "ipam/prefixes", {"within": prefix["prefix"], **scope, "fields": "prefix"}
+        )
+        child_count = len(children)
+        size = network.num_addresses
+        # Collapse so nested children don't count the same addresses twice
+        child_networks = ipaddress.collapse_addresses(
+            ipaddress.ip_network(child["prefix"]) for child in children
+        )
+        used = sum(child.num_addresses for child in child_networks)
+    else:
+        child_count = netbox.get(
+            "ipam/prefixes", params={"within": prefix["prefix"], **scope, "limit": 1}
+        )["count"]
+        size = _usable_address_count(network, prefix.get("is_pool", False))
+        used = ip_count
+
+    if prefix.get("mark_utilized"):
+        utilization = 100.0
+    else:
+        utilization = round(min(used / size * 100, 100), 1) if size else 0.0
+    return {
+        "id": prefix["id"],
+        "prefix": prefix["prefix"],
+        "vrf": (prefix.get("vrf") or {}).get("display"),
+        "status": _choice_value(prefix.get("status")),
+        "size": size,
+        "child_prefixes": child_count,
+        "ip_addresses": ip_count,
+        "utilization": utilization,
+    }
+
+
+@mcp.tool
+def netbox_find_ip_conflicts(
+    parent: str | None = None,
+    vrf_id: int | None = None,
+) -> dict[str, Any]:
+    """
+    Find duplicate IP addresses and duplicate prefixes.
+
+    Two IP addresses conflict when they have the same host address in the same VRF
+    (or both in the global table), whatever their mask. Addresses with a shared role
+    (anycast, VIP, VRRP, HSRP, GLBP, CARP) are expected to repeat and are skipped.
+    Two prefixes conflict when the same network is defined twice in the same VRF;
+    nested prefixes are normal hierarchy and are not reported.
+
+    Args:
+        parent: Optional CIDR to limit the scan to, e.g. "10.0.0.0/16" (recommended on
+                large installations, as every matching address is fetched)
+        vrf_id: Optional VRF ID to limit the scan to
+
+    Returns:
+        Dict with:
+            - summary: Number of duplicate IP and prefix groups
+            - duplicate_ips: Groups with address, vrf and the conflicting objects
+                             (id, address, status and assigned interface)
+            - duplicate_prefixes: Groups with prefix, vrf and the conflicting objects
+                                  (id, status and scope)
+    """
+    ip_params: dict[str, Any] = {"fields": "id,address,vrf,status,role,assigned_object"}
+    prefix_params: dict[str, Any] = {"fields": "id,prefix,vrf,status,scope"}
+    if parent:
+        ip_params["parent"] = parent
+        prefix_params["within_include"] = parent
+    if vrf_id is not None:
+        ip_params["vrf_id"] = vrf_id
+        prefix_params["vrf_id"] = vrf_id
+
+    ip_groups: dict[tuple[Any, str], list[dict[str, Any]]] = {}
+    for ip in _get_all_objects("ipam/ip-addresses", ip_params):
+        if _choice_value(ip.get("role")) in NONUNIQUE_IP_ROLES:
+            continue
+        key = ((ip.get("vrf") or {}).get("id"), str(ipaddress.ip_interface(ip["address"]).ip))
+        ip_groups.setdefault(key, []).append(ip)
+
+    prefix_groups: dict[tuple[Any, str], list[dict[str, Any]]] = {}
+    for prefix in _get_all_objects("ipam/prefixes", prefix_params):
+        key = ((prefix.get("vrf") or {}).get("id"), str(ipaddress.ip_network(prefix["prefix"])))
+        prefix_groups.setdefault(key, []).append(prefix)
+
+    duplicate_ips = [
+        {
+            "address": host,
+            "vrf": (group[0].get("vrf") or {}).get("display"),
+            "objects": [
+                {
+                    "id": ip["id"],
+                    "address": ip["address"],
+                    "status": _choice_value(ip.get("status")),
+                    "assigned_object": (ip.get("assigned_object") or {}).get("display"),
+                }
+                for ip in group
+            ],
+        }
+        for (_, host), group in ip_groups.items()
+        if len(group) > 1
+    ]
+    duplicate_prefixes = [
+        {
+            "prefix": network,
+            "vrf": (group[0].get("vrf") or {}).get("display"),
+            "objects": [
+                {
+                    "id": prefix["id"],
+                    "status": _choice_value(prefix.get("status")),
+                    "scope": (prefix.get("scope") or {}).get("display"),
+                }
+                for prefix in group
+            ],
+        }
+        for (_, network), group in prefix_groups.items()
+        if len(group) > 1
+    ]
+    return {
+        "summary": {
+            "duplicate_ips": len(duplicate_ips),
+            "duplicate_prefixes": len(duplicate_prefixes),
+        },
+        "duplicate_ips": duplicate_ips,
+        "duplicate_prefixes": duplicate_prefixes,
+    }
+
+
+@mcp.tool
+def netbox_get_vlan_translation_policies(policy_id: int | None = None) -> list[dict[str, Any]]:
+    """
+    Get VLAN translation policies with their rules and the interfaces applying them.
+
+    Resolves policy, rule and interface objects into one response, so a policy
+    can be reviewed without querying each object type separately.
+
+    Args:
+        policy_id: Optional ID of a single ipam.vlantranslationpolicy (default: all policies)
+
+    Returns:
+        One entry per policy, each with:
+            - id, name, description: The policy itself
+            - rules: Translation rules ordered by local VID (local_vid, remote_vid, description)
+            - interfaces: Device and VM interfaces using the policy (id, display, parent)
+    """
+    if policy_id is not None:
+        policies = [netbox.get("ipam/vlan-translation-policies", id=policy_id)]
+    else:
+        policies = _get_all_objects("ipam/vlan-translation-policies")
+    if not policies:
+        return []
+
+    policy_ids = [policy["id"] for policy in policies]
+    rules = _get_all_objects("ipam/vlan-translation-rules", {"policy_id": policy_ids})
+    interfaces = []
+    for endpoint, parent_field in (
+        ("dcim/interfaces", "device"),
+        ("virtualization/interfaces", "virtual_machine"),
+    ):
+        interfaces += _get_all_objects(
+            endpoint,
+            {
+                "vlan_translation_policy_id": policy_ids,
+                "fields": f"id,display,{parent_field},vlan_translation_policy",
+            },
+        )
+
+    report = {
+        policy["id"]: {
+            "id": policy["id"],
+            "name": policy.get("name"),
+            "description": policy.get("description"),
+            "rules": [],
+            "interfaces": [],
+        }
+        for policy in policies
+    }
+    for rule in sorted(rules, key=lambda rule: rule.get("local_vid") or 0):
+        entry = report.get((rule.get("policy") or {}).get("id"))
+        if entry is not None:
+            entry["rules"].append(
+                {key: rule.get(key) for key in ("local_vid", "remote_vid", "description")}
+            )
+    for interface in interfaces:
+        entry = report.get((interface.get("vlan_translation_policy") or {}).get("id"))
+        if entry is not None:
+            parent = interface.get("device") or interface.get("virtual_machine") or {}
+            entry["interfaces"].append(
+                {
+                    "id": interface.get("id"),
+                    "display": interface.get("display"),
+                    "parent": parent.get("display"),
+                }
+            )
+    return list(report.values())
+
+
+@mcp.tool
+def netbox_audit_prefix_vlan_consistency(
+    site_id: int | None = None,
+    limit: Annotated[int, Field(default=50, ge=1, le=1000)] = 50,
+) -> dict[str, Any]:
+    """
+    Audit prefixes and VLANs for common consistency problems.
+
+    Runs four checks and returns a cleanup list ordered by priority:
+    - high: prefix_vlan_site_mismatch - prefix scoped to a site other than its VLAN's site
+    - medium: vlan_without_site_or_tags - VLAN with no site and no tags (unscoped, unlabelled)
+    - medium: vlan_without_prefix - VLAN that no prefix is assigned to
+    - low: prefix_without_role - prefix with no role set
+
+    Args:
+        site_id: Optional site ID to restrict the audit to one site's prefixes and VLANs.
+                 VLANs and prefixes at other sites are still consulted for the checks.
+        limit: Maximum number of issues to return (default 50, max 1000).
+               The summary always reports the full count per check.
+
+    Returns:
+        Dict with:
+            - summary: Count of issues found per check
+            - issues: Prioritized list of issues, each with priority, check,
+                      object_type, id, display and detail
+    """
+    prefix_params: dict[str, Any] = {"fields": "id,display,role,vlan,scope_type,scope_id"}
+    vlan_params: dict[str, Any] = {"fields": "id,display,site,tags"}
+    if site_id is not None:
+        prefix_params["site_id"] = site_id
+        vlan_params["site_id"] = site_id
+
+    prefixes = _get_all_objects("ipam/prefixes", prefix_params)
+    vlans = _get_all_objects("ipam/vlans", vlan_params)
+
+    vlan_sites = {vlan["id"]: (vlan.get("site") or {}).get("id") for vlan in vlans}
+    vlans_with_prefixes = {prefix["vlan"]["id"] for prefix in prefixes if prefix.get("vlan")}
+    if site_id is not None:
+        # This site's prefixes may use other sites' VLANs, and its VLANs may be used by
+        # prefixes elsewhere, so both lookups ignore the site filter
+        referenced = sorted(vlans_with_prefixes - vlan_sites.keys())
+        if referenced:
+            for vlan in _get_all_objects("ipam/vlans", {"id": referenced, "fields": "id,site"}):
+                vlan_sites[vlan["id"]] = (vlan.get("site") or {}).get("id")
+        if vlans:
+            used = _get_all_objects(
+                "ipam/prefixes", {"vlan_id": [vlan["id"] for vlan in vlans], "fields": "vlan"}
+            )
+            vlans_with_prefixes |= {prefix["vlan"]["id"] for prefix in used if prefix.get("vlan")}
+
+    issues: list[dict[str, Any]] = []
+    for prefix in prefixes:
+        vlan = prefix.get("vlan")
+        if vlan and prefix.get("scope_type") == "dcim.site":
+            vlan_site = vlan_sites.get(vlan["id"])
+            if vlan_site is not None and vlan_site != prefix.get("scope_id"):
+                issues.append(
+                    _audit_issue(
+                        "high",
+                        "prefix_vlan_site_mismatch",
+                        "ipam.prefix",
+                        prefix,
+                        f"Prefix site {prefix.get('scope_id')} differs from VLAN "
+                        f"{vlan.get('display', vlan['id'])} site {vlan_site}",
+                    )
+                )
+        if not prefix.get("role"):
+            issues.append(
+                _audit_issue("low", "prefix_without_role", "ipam.prefix", prefix, "No role set")
+            )
+
+    for vlan in vlans:
+        if not vlan.get("site") and not vlan.get("tags"):
+            issues.append(
+                _audit_issue(
+                    "medium",
+                    "vlan_without_site_or_tags",
+                    "ipam.vlan",
+                    vlan,
+                    "No site and no tags",
+                )
+            )
+        if vlan["id"] not in vlans_with_prefixes:
+            issues.append(
+                _audit_issue(
+                    "medium", "vlan_without_prefix", "ipam.vlan", vlan, "No prefix assigned"
+                )
+            )
+
+    priority_order = {"high": 0, "medium": 1, "low": 2}
+    issues.sort(key=lambda issue: priority_order[issue["priority"]])
+
+    summary = dict.fromkeys(
+        (
+            "prefix_vlan_site_mismatch",
+            "vlan_without_site_or_tags",
+            "vlan_without_prefix",
+            "prefix_without_role",
+        ),
+        0,
+    )
+    for issue in issues:
+        summary[issue["check"]] += 1
+
+    return {"summary": summary, "issues": issues[:limit]}
+
+
+@mcp.tool
+def netbox_audit_tunnel_terminations(
+    tunnel_id: int | None = None,
+    limit: Annotated[int, Field(default=50, ge=1, le=1000)] = 50,
+) -> dict[str, Any]:
+    """
+    Audit VPN tunnel terminations for outside IPs that don't match the terminating interface.
+
+    Runs two checks and returns a cleanup list ordered by priority:
+    - high: outside_ip_not_on_interface - outside IP is unassigned or assigned to
+      a different interface than the one terminating the tunnel
+    - medium: termination_without_outside_ip - termination has no outside IP in IPAM
+
+    Args:
+        tunnel_id: Optional vpn.tunnel ID to audit a single tunnel's terminations
+        limit: Maximum number of issues to return (default 50, max 1000).
+               The summary always reports the full count per check.
+
+    Returns:
+        Dict with:
+            - summary: Count of issues found per check
+            - issues: Prioritized list of issues, each with priority, check,
+                      object_type, id, display and detail
+    """
+    params: dict[str, Any] = {
+        "fields": "id,display,tunnel,termination_type,termination_id,termination,outside_ip"
+    }
+    if tunnel_id is not None:
+        params["tunnel_id"] = tunnel_id
+    terminations = _get_all_objects("vpn/tunnel-terminations", params)
+
+    ip_ids = sorted({t["outside_ip"]["id"] for t in terminations if t.get("outside_ip")})
+    ips: list[dict[str, Any]] = []
+    # Look IPs up in batches to keep the id filter within URL length limits
+    for start in range(0, len(ip_ids), 100):
+        ips += _get_all_objects(
+            "ipam/ip-addresses",
+            {
+                "id": ip_ids[start : start + 100],
+                "fields": "id,address,assigned_object_type,assigned_object_id",
+            },
+        )
+    ip_assignments = {
+        ip["id"]: (ip.get("assigned_object_type"), ip.get("assigned_object_id")) for ip in ips
+    }
+
+    issues: list[dict[str, Any]] = []
+    for termination in terminations:
+        tunnel = (termination.get("tunnel") or {}).get("display")
+        interface = (termination.get("termination") or {}).get("display")
+        outside_ip = termination.get("outside_ip")
+        if not outside_ip:
+            issues.append(
+                _audit_issue(
+                    "medium",
+                    "termination_without_outside_ip",
+                    "vpn.tunneltermination",
+                    termination,
+                    f"Tunnel {tunnel} termination on {interface} has no outside IP",
+                )
+            )
+            continue
+        expected = (termination.get("termination_type"), termination.get("termination_id"))
+        if ip_assignments.get(outside_ip["id"]) != expected:
+            issues.append(
+                _audit_issue(
+                    "high",
+                    "outside_ip_not_on_interface",
+                    "vpn.tunneltermination",
+                    termination,
+                    f"Outside IP {outside_ip.get('address')} of tunnel {tunnel} "
+                    f"is not assigned to terminating interface {interface}",
+                )
+            )
+
+    priority_order = {"high": 0, "medium": 1}
+    issues.sort(key=lambda issue: priority_order[issue["priority"]])
+
+    summary = dict.fromkeys(("outside_ip_not_on_interface", "termination_without_outside_ip"), 0)
+    for issue in issues:
+        summary[issue["check"]] += 1
+
+    return {"summary": summary, "issues": issues[:limit]}
+
+
+@mcp.tool
+def netbox_audit_data_quality(
+    checks: list[str] | None = None,
+    site_id: int | None = None,
+    limit: Annotated[int, Field(default=50, ge=1, le=1000)] = 50,
+) -> dict[str, Any]:
+    """
+    Audit NetBox for common data-quality gaps, each check being one filtered query.
+
+    Available checks (all run by default):
+    - medium: device_without_primary_ip - device has no primary IPv4 or IPv6 address
+    - medium: vm_without_primary_ip - virtual machine has no primary IP address
+    - low: device_without_serial - device serial number is empty
+    - low: device_without_platform - device has no platform set
+    - low: interface_type_other - interface type left as "other"
+    - low: ip_without_assignment - IP address not assigned to any interface
+
+    Args:
+        checks: Names of the checks to run (default: all)
+        site_id: Optional site ID to restrict the audit to; ip_without_assignment
+                 cannot be filtered by site and always covers every IP address
+        limit: Maximum number of issues to return (default 50, max 1000).
+               The summary always reports the full count per check.
+
+    Returns:
+        Dict with:
+            - summary: Count of offending objects per check
+            - issues: Prioritized list of issues, each with priority, check,
+                      object_type, id, display and detail
+    """
+    selected = checks or list(DATA_QUALITY_CHECKS)
+    unknown = [check for check in selected if check not in DATA_QUALITY_CHECKS]
+    if unknown:
+        raise ValueError(
+            f"Unknown checks {unknown}. Must be one of: {', '.join(DATA_QUALITY_CHECKS)}"
+        )
+
+    summary: dict[str, int] = {}
+    issues: list[dict[str, Any]] = []
+    for check in selected:
+        priority, object_type, filters, site_filter, detail = DATA_QUALITY_CHECKS[check]
+        params = {**filters, "fields": "id,display", "limit": limit}
+        if site_id is not None and site_filter:
+            params[site_filter] = site_id
+        endpoint, fallback = _get_endpoint_info(object_type)
+        response = netbox.get(endpoint, params=params, fallback_endpoint=fallback)
+        summary[check] = response.get("count", 0)
+        issues += [
+            _audit_issue(priority, check, object_type, obj, detail)
+            for obj in response.get("results", [])
+        ]
+
+    priority_order = {"high": 0, "medium": 1, "low": 2}
+    issues.sort(key=lambda issue: priority_order[issue["priority"]])
+    return {"summary": summary, "issues": issues[:limit]}
+
+
+def _audit_issue(
+    priority: str, check: str, object_type: str, obj: dict[str, Any], detail: str
+) -> dict[str, Any]:
+    """Build a single audit issue entry for an offending object."""
+    return {
+        "priority": priority,
+        "check": check,
+        "object_type": object_type,
+        "id": obj.get("id"),
+        "display": obj.get("display"),
+        "detail": detail,
+    }
+
+
+@mcp.tool
+def netbox_get_asn_usage(asn: int, device_custom_field: str | None = None) -> dict[str, Any]:
+    """
+    Show where an ASN is used: its sites, providers, devices and BGP custom fields.
+
+    Core NetBox relates ASNs to sites and providers only. Devices are matched through
+    a device custom field holding the ASN number, when one is given.
+
+    Args:
+        asn: The AS number (e.g. 65001), not the NetBox object ID
+        device_custom_field: Optional name of an integer device custom field holding the
+                             device's ASN (e.g. "bgp_asn")
+
+    Returns:
+        Dict with:
+            - asn: The ASN record (id, asn, display, rir, tenant, description)
+            - custom_fields: The ASN's custom fields that have a value
+            - sites: Sites the ASN is assigned to (id, display)
+            - providers: Circuit providers the ASN is assigned to (id, display)
+            - devices: Devices whose custom field matches the ASN (id, display, site),
+                       or an empty list when device_custom_field is not given
+    """
+    matches = netbox.get("ipam/asns", params={"asn": asn}).get("results", [])
+    if not matches:
+        raise ValueError(f"ASN {asn} not found in NetBox")
+    record = matches[0]
+
+    sites = _get_all_objects("dcim/sites", {"asn_id": record["id"], "fields": "id,display"})
+    providers = _get_all_objects(
+        "circuits/providers", {"asn_id": record["id"], "fields": "id,display"}
+    )
+    devices = []
+    if device_custom_field:
+        devices = [
+            {
+                "id": device.get("id"),
+                "display": device.get("display"),
+                "site": (device.get("site") or {}).get("display"),
+            }
+            for device in _get_all_objects(
+                "dcim/devices", {f"cf_{device_custom_field}": asn, "fields": "id,display,site"}
+            )
+        ]
+
+    return {
+        "asn": {
+            "id": record.get("id"),
+            "asn": record.get("asn"),
+            "display": record.get("display"),
+            "rir": (record.get("rir") or {}).get("display"),
+            "tenant": (record.get("tenant") or {}).get("display"),
+            "description": record.get("description"),
+        },
+        "custom_fields": {
+            name: value
+            for name, value in (record.get("custom_fields") or {}).items()
+            if value not in (None, "", [], {})
+        },
+        "sites": [{"id": site.get("id"), "display": site.get("display")} for site in sites],
+        "providers": [
+            {"id": provider.get("id"), "display": provider.get("display")}
+            for provider in providers
+        ],
+        "devices": devices,
+    }
+
+
+@mcp.tool
+def netbox_get_device_lifecycle_report(
+    eol_field: str = "eol_date",
+    support_field: str | None = None,
+    within_days: Annotated[int, Field(default=180, ge=0)] = 180,
+    site_id: int | None = None,
+) -> dict[str, Any]:
+    """
+    Report devices past or approaching end-of-life, grouped by site, from date custom fields.
+
+    Reads date custom fields (YYYY-MM-DD) on each device. A device is listed when its
+    EOL date, or its support end date if support_field is given, is already past or
+    falls within the next within_days days.
+
+    Args:
+        eol_field: Name of the device custom field holding the end-of-life date
+                   (default "eol_date")
+        support_field: Optional name of a device custom field holding the support
+                       contract end date
+        within_days: How many days ahead counts as approaching (default 180)
+        site_id: Optional site ID to restrict the report to one site
+
+    Returns:
+        Dict with:
+            - sites: Site name -> devices (id, display, eol_date, eol_status and, with
+              support_field, support_end and support_status), soonest date first.
+              Status is "past", "approaching" or "ok" ("unknown" when no date is set).
+            - summary: Count of listed devices per EOL status
+            - devices_without_eol_date: Number of devices with no EOL date set
+    """
+    params: dict[str, Any] = {"fields": "id,display,site,custom_fields"}
+    if site_id is not None:
+        params["site_id"] = site_id
+    devices = _get_all_objects("dcim/devices", params)
+
+    today = datetime.date.today()
+    horizon = today + datetime.timedelta(days=within_days)
+
+    sites: dict[str, list[dict[str, Any]]] = {}
+    without_eol = 0
+    for device in devices:
+        custom_fields = device.get("custom_fields") or {}
+        eol_date, eol_status = _lifecycle_status(custom_fields.get(eol_field), today, horizon)
+        if eol_date is None:
+            without_eol += 1
+        entry = {
+            "id": device.get("id"),
+            "display": device.get("display"),
+            "eol_date": eol_date,
+            "eol_status": eol_status,
+        }
+        statuses = {eol_status}
+        if support_field:
+            entry["support_end"], entry["support_status"] = _lifecycle_status(
+                custom_fields.get(support_field), today, horizon
+            )
+            statuses.add(entry["support_status"])
+        if statuses & {"past", "approaching"}:
+            site = (device.get("site") or {}).get("display") or "(no site)"
+            sites.setdefault(site, []).append(entry)
+
+    summary = dict.fromkeys(("past", "approaching", "ok", "unknown"), 0)
+    for site_devices in sites.values():
+        site_devices.sort(key=lambda entry: entry.get("eol_date") or "9999-12-31")
+        for entry in site_devices:
+            summary[entry["eol_status"]] += 1
+
+    return {"sites": sites, "summary": summary, "devices_without_eol_date": without_eol}
+
+
+def _lifecycle_status(
+    value: Any, today: datetime.date, horizon: datetime.date
+) -> tuple[str | None, str]:
+    """Classify a date custom field value as past, approaching (before horizon) or ok."""
+    try:
+        date = datetime.date.fromisoformat(str(value)[:10])
+    except ValueError:
+        return None, "unknown"
+    if date < today:
+        return date.isoformat(), "past"
+    if date <= horizon:
+        return date.isoformat(), "approaching"
+    return date.isoformat(), "ok"
+
+
+@mcp.tool
+def netbox_get_event_pipelines(enabled_only: bool = False) -> dict[str, Any]:
+    """
+    Show how NetBox event rules are wired to webhooks, to check that event pipelines work.
+
+    Each event rule is listed with the object types and events that trigger it and the
+    webhook it calls. Rules pointing at a webhook that no longer exists, and webhooks no
+    rule calls, are called out. Webhook secrets and additional headers are never returned.
+    This only reads configuration; it does not send test deliveries.
+
+    Args:
+        enabled_only: Only include enabled event rules
+
+    Returns:
+        Dict with:
+            - rules: Event rules with id, name, enabled, object_types, event_types,
+                     conditions, action_type, webhook (for webhook actions, or null)
+                     and problem (null unless the webhook is missing)
+            - unused_webhooks: Webhooks no listed rule calls
+    """
+    rules = _get_all_objects("extras/event-rules", {"enabled": "true"} if enabled_only else None)
+    webhooks = {webhook["id"]: webhook for webhook in _get_all_objects("extras/webhooks")}
+
+    pipelines = []
+    called: set[int] = set()
+    for rule in rules:
+        action_type = _choice_value(rule.get("action_type"))
+        event_types = rule.get("event_types") or [
+            flag.removeprefix("type_") for flag in LEGACY_EVENT_TYPE_FLAGS if rule.get(flag)
+        ]
+        pipeline: dict[str, Any] = {
+            "id": rule.get("id"),
+            "name": rule.get("name"),
+            "enabled": rule.get("enabled"),
+            "object_types": rule.get("object_types", []),
+            "event_types": event_types,
+            "conditions": rule.get("conditions"),
+            "action_type": action_type,
+            "webhook": None,
+            "problem": None,
+        }
+        if action_type == "webhook":
+            webhook_id = rule.get("action_object_id")
+            called.add(webhook_id)
+            if webhook_id in webhooks:
+                pipeline["webhook"] = _webhook_summary(webhooks[webhook_id])
+            else:
+                pipeline["problem"] = f"Webhook {webhook_id} does not exist"
+        pipelines.append(pipeline)
+
+    return {
+        "rules": pipelines,
+        "unused_webhooks": [
+            _webhook_summary(webhook)
+            for webhook_id, webhook in webhooks.items()
+            if webhook_id not in called
+        ],
+    }
+
+
+def _webhook_summary(webhook: dict[str, Any]) -> dict[str, Any]:
+    """Reduce a webhook to WEBHOOK_SUMMARY_FIELDS."""
+    return {field: webhook.get(field) for field in WEBHOOK_SUMMARY_FIELDS}
+
+
+@mcp.tool
+def netbox_get_server_health(
+    window_minutes: Annotated[int, Field(default=5, ge=1, le=60)] = 5,
+) -> dict[str, Any]:
+    """
+    Report how the NetBox integration has been behaving recently, to decide whether to back off.
+
+    Statistics are kept in memory for this server process only. NetBox errors count transport
+    failures, 401/403, 429 and 5xx responses; each retry counts as its own request, while
+    cached responses are not counted.
+
+    Args:
+        window_minutes: How far back to look (default 5, max 60)
+
+    Returns:
+        Dict with status ("healthy", "degraded" or "idle" when NetBox was not called),
+        tool call counts and error rate, NetBox request counts, error rate and latency
+        percentiles in ms, and the retry policy in effect
+    """
+    window = window_minutes * 60
+    cutoff = time.monotonic() - window
+    with _recent_tool_calls_lock:
+        recent_calls = list(_recent_tool_calls)
+    outcomes = [ok for at, ok in recent_calls if at >= cutoff]
+    failed = outcomes.count(False)
+    requests = netbox.request_stats(window)
+
+    if not requests["requests"]:
+        status = "idle"
+    elif (
+        requests["error_rate"] >= DEGRADED_ERROR_RATE
+        or (requests["latency_ms"]["p95"] or 0) >= DEGRADED_P95_MS
+    ):
+        status = "degraded"
+    else:
+        status = "healthy"
+
+    return {
+        "status": status,
+        "window_minutes": window_minutes,
+        "tool_calls": {
+            "total": len(outcomes),
+            "failed": failed,
+            "error_rate": round(failed / len(outcomes), 3) if outcomes else 0.0,
+        },
+        "netbox_requests": requests,
+        "retry_policy": {
+            "max_retries": netbox.max_retries,
+            "retry_backoff_seconds": netbox.retry_backoff,
+        },
+    }
+
+
+@mcp.resource("netbox://summary/deployment", mime_type="application/json")
+def deployment_summary() -> dict[str, Any]:
+    """
+    Summary of the whole NetBox deployment for broad "state of the network" questions.
+
+    Covers object totals, sites and devices per top-level region, approximate IPv4
+    utilization and change volume over the last 7 days. Rebuilt at most every 5 minutes;
+    query the tools for live or detailed data.
+    """
+    global _deployment_summary_cache
+    summary = _fresh_deployment_summary()
+    if summary is not None:
+        return summary
+
+    # One reader rebuilds; concurrent readers wait for it and re-check instead of
+    # each running the full set of queries
+    with _deployment_summary_lock:
+        summary = _fresh_deployment_summary()
+        if summary is None:
+            summary = _build_deployment_summary()
+            _deployment_summary_cache = (time.monotonic(), summary)
+    return summary
+
+
+def _fresh_deployment_summary() -> dict[str, Any] | None:
+    """The cached deployment summary, or None when there is none or it has expired."""
+    cached = _deployment_summary_cache
+    if cached is None or time.monotonic() - cached[0] >= DEPLOYMENT_SUMMARY_TTL:
+        return None
+    return cached[1]
+
+
+@mcp.resource("netbox://results/{result_id}", mime_type="application/json")
+def scratchpad_result(result_id: str) -> Any:
+    """
+    Full version of a tool response that was truncated to fit MAX_RESPONSE_CHARS.
+
+    The URI is returned in the response's "truncated" entry. Only the most recent
+    results are kept, in server memory, and they are lost on restart.
+    """
+    with _scratchpad_lock:
+        data = _scratchpad.get(result_id)
+    if data is None:
+        raise NotFoundError(f"Result {result_id} has expired or does not exist")
+    return data
+
+
+@mcp.prompt
+def audit_site_primary_ips(site: str) -> str:
+    """Find devices and VMs in a site that have no primary IP address."""
+    return f"""Audit the NetBox site "{site}" for devices and virtual machines without a primary IP.
+
+1. Resolve the site with netbox_get_objects (object_type "dcim.site", filters {{"q": "{site}"}},
+   fields ["id", "name", "slug"]). If several sites match, ask which one is meant.
+2. List devices with netbox_get_objects (object_type "dcim.device", filters
+   {{"site_id": <id>, "has_primary_ip": false}}, fields ["id", "name", "role", "status"]).
+   Page with offset until every device is seen; use the response "count" for the total.
+3. List virtual machines the same way (object_type "virtualization.virtualmachine", filters
+   {{"site_id": <id>, "has_primary_ip": false}}).
+4. For up to 5 devices, check with netbox_get_objects (object_type "ipam.ipaddress",
+   filters {{"device_id": <id>}}) whether addresses exist that could be made primary.
+
+Report the totals first, then a table of objects grouped by role and status, marking
+which ones already have candidate addresses. Do not suggest changes for offline or
+decommissioning objects."""
+
+
+@mcp.prompt
+def document_rack(rack: str) -> str:
+    """Write documentation for a rack: elevation, devices, cabling and power."""
+    return f"""Write documentation for the NetBox rack "{rack}".
+
+1. Resolve the rack with netbox_get_objects (object_type "dcim.rack", filters {{"q": "{rack}"}},
+   fields ["id", "name", "site", "location", "u_height", "status"]). If several racks match,
+   ask which one is meant.
+2. Get the unit-by-unit layout with netbox_get_rack_elevation.
+3. Get every cable in the rack with netbox_audit_rack_cables and note any flagged issues.
+4. List the power ports with netbox_get_objects (object_type "dcim.powerport", filters
+   {{"rack_id": <id>}}, fields ["device", "name", "maximum_draw", "allocated_draw", "cable"]).
+
+Produce a Markdown document with: a summary (site, location, height, utilization), the
+front and rear elevation as a table from top to bottom, a cabling table and the power
+budget. List data quality issues (unracked devices, single-ended cables) at the end."""
+
+
+@mcp.prompt
+def plan_device_decommission(device: str) -> str:
+    """Gather everything needed to plan the decommissioning of a device."""
+    return f"""Prepare a decommissioning plan for the NetBox device "{device}". This is a
+read-only investigation: describe the changes, do not attempt to make them.
+
+1. Resolve the device with netbox_get_objects (object_type "dcim.device", filters
+   {{"q": "{device}"}}, fields ["id", "name", "site", "rack", "role", "status",
+   "primary_ip4", "primary_ip6"]). If several devices match, ask which one is meant.
+2. Get its interfaces with netbox_get_device_interface_summary and, for every cabled
+   interface, the far end with netbox_trace_cable_path (object_type "dcim.interface").
+3. List its IP addresses (object_type "ipam.ipaddress", filters {{"device_id": <id>}}),
+   the services on it (object_type "ipam.service", filters {{"device_id": <id>}}) and
+   any virtual chassis, cluster or module relationships shown on the device.
+4. Check recent activity with netbox_get_changelogs (filters
+   {{"changed_object_id": <id>}}).
+
+Report the dependencies that must be migrated first (connected neighbours, services,
+addresses to release), then an ordered list of NetBox changes to make, ending with
+setting the status to decommissioning and removing cables and the device."""
+
+
+def _build_deployment_summary() -> dict[str, Any]:
+    """Query NetBox for the figures behind the deployment summary resource."""
+    totals = {
+        object_type: _count_objects(NETBOX_OBJECT_TYPES[object_type]["endpoint"])
+        for object_type in (
+            "dcim.site",
+            "dcim.rack",
+            "dcim.device",
+            "virtualization.virtualmachine",
+            "circuits.circuit",
+            "ipam.prefix",
+            "ipam.ipaddress",
+            "ipam.vlan",
+        )
+    }
+
+    top_level_regions = _get_all_objects(
+        "dcim/regions", {"parent_id": "null", "fields": "id,display"}
+    )
+    regions = [
+        {
+            "region": region.get("display"),
+            "sites": _count_objects("dcim/sites", {"region_id": region["id"]}),
+            "devices": _count_objects("dcim/devices", {"region_id": region["id"]}),
+        }
+        for region in top_level_regions
+    ]
+
+    # Leaf prefixes (no children) that aren't containers hold the assignable space
+    leaf_prefixes = _get_all_objects(
+        "ipam/prefixes",
+        {"family": 4, "children": 0, "status__n": "container", "fields": "prefix,is_pool"},
+    )
+    ipv4_capacity = sum(
+        _usable_address_count(ipaddress.ip_network(p["prefix"]), p.get("is_pool", False))
+        for p in leaf_prefixes
+    )
+    ipv4_used = _count_objects("ipam/ip-addresses", {"family": 4})
+
+    since = datetime.datetime.now(datetime.UTC) - datetime.timedelta(days=7)
+    endpoint, fallback = _get_endpoint_info("core.objectchange")
+    change_params = {"time_after": since.isoformat()}
+    changes = {
+        action: _count_objects(endpoint, {**change_params, "action": action}, fallback)
+        for action in ("create", "update", "delete")
+    }
+
+    return {
+        "generated_at": datetime.datetime.now(datetime.UTC).isoformat(),
+        "totals": totals,
+        "regions": regions,
+        "ipv4_utilization": {
+            "used": ipv4_used,
+            "capacity": ipv4_capacity,
+            "percent": round(ipv4_used / ipv4_capacity * 100, 1) if ipv4_capacity else None,
+        },
+        "changes_last_7_days": changes,
+    }
+
+
+def _count_objects(
+    endpoint: str, params: dict[str, Any] | None = None, fallback_endpoint: str | None = None
+) -> int:
+    """Return how many objects a list endpoint matches, fetching a single row."""
+    response = netbox.get(
+        endpoint, params={**(params or {}), "limit": 1}, fallback_endpoint=fallback_endpoint
+    )
+    return response["count"]
+
+
+def _usable_address_count(
+    network: ipaddress.IPv4Network | ipaddress.IPv6Network, is_pool: bool
+) -> int:
+    """Count usable addresses the way NetBox does for prefix utilization."""
+    size = network.num_addresses
+    if is_pool:
+        return size
+    if network.version == 4 and network.prefixlen < 31:
+        return size - 2  # Network and broadcast addresses
+    if network.version == 6 and network.prefixlen < 127:
+        return size - 1  # Subnet-router anycast address
+    return size
+
+
+def _limit_response_size(
+    data: Any, render: Callable[[list[dict[str, Any]]], dict[str, Any]] | None = None
+) -> Any:
+    """
+    Shrink a response that exceeds MAX_RESPONSE_CHARS so it fits the model's context.
+
+    Paginated responses keep their count and as many leading results as fit; single
+    objects drop their largest fields. Either way a "truncated" entry lists what was
+    left out, the fields available, how to narrow the query and the resource URI the
+    full response can be read from.
+
+    Args:
+        data: The response to limit
+        render: Optional function turning the results into the entries that replace
+                them, e.g. {"csv": "..."}; sizes are measured on the rendered response
+    """
+    if not isinstance(data, dict):
+        return data
+
+    def rendered(response: dict[str, Any]) -> dict[str, Any]:
+        if render is None or not isinstance(response.get("results"), list):
+            return response
+        rest = {key: value for key, value in response.items() if key != "results"}
+        return {**rest, **render(response["results"])}
+
+    if not max_response_chars:
+        return rendered(data)
+    size = _response_chars(rendered(data))
+    if size <= max_response_chars:
+        return rendered(data)
+
+    truncated: dict[str, Any] = {
+        "response_chars": size,
+        "max_response_chars": max_response_chars,
+        "guidance": (
+            "The full response was too large. Request only the fields you need with "
+            "fields=[...], use brief=True, add filters or lower limit, and page with offset."
+        ),
+        "resource_uri": _store_scratchpad(rendered(data)),
+    }
+    if isinstance(data.get("results"), list):
+        results = data["results"]
+        fields = sorted({key for obj in results if isinstance(obj, dict) for key in obj})
+        summary = {key: value for key, value in data.items() if key != "results"}
+        summary["truncated"] = {**truncated, "available_fields": fields}
+        kept = len(results)
+        while True:
+            summary["results"] = results[:kept]
+            summary["truncated"]["results_returned"] = kept
+            if kept == 0 or _response_chars(rendered(summary)) <= max_response_chars:
+                return rendered(summary)
+            kept //= 2
+
+    summary = dict(data)
+    summary["truncated"] = {**truncated, "available_fields": sorted(data), "omitted_fields": []}
+    by_size = sorted(data, key=lambda key: _response_chars(data[key]), reverse=True)
+    for key in by_size:
+        if _response_chars(summary) <= max_response_chars:
+            break
+        if key in ("id", "url", "display"):
+            continue
+        del summary[key]
+        summary["truncated"]["omitted_fields"].append(key)
+    return summary
+
+
+def _store_scratchpad(data: Any) -> str:
+    """Keep a full response for netbox://results/{result_id}, evicting the oldest entries."""
+    result_id = uuid.uuid4().hex
+    # Called from tool worker threads, so every scratchpad access happens under the lock
+    with _scratchpad_lock:
+        _scratchpad[result_id] = data
+        while len(_scratchpad) > SCRATCHPAD_MAX_ENTRIES:
+            _scratchpad.popitem(last=False)
+    return f"netbox://results/{result_id}"
+
+
+def _response_chars(data: Any) -> int:
+    """Length of the JSON a response serializes to, as the model sees it."""
+    return len(json.dumps(data, default=str))
+
+
+def _normalize_response(data: Any) -> Any:
+    """
+    Apply the configured unit and timezone normalization to a NetBox response, in place.
+
+    Converted units are added next to the raw field (speed -> speed_gbps) so filters
+    and IDs still line up with NetBox; timestamps are rewritten as the same instant
+    in DISPLAY_TIMEZONE. Nested objects and lists are handled recursively.
+    """
+    if not normalize_units and display_timezone is None:
+        return data
+    if isinstance(data, list):
+        for item in data:
+            _normalize_response(item)
+    elif isinstance(data, dict):
+        for key, value in list(data.items()):
+            if isinstance(value, dict | list):
+                _normalize_response(value)
+            elif normalize_units and key in UNIT_CONVERSIONS and _is_number(value):
+                suffix, factor = UNIT_CONVERSIONS[key]
+                data[f"{key}_{suffix}"] = round(value * factor, 3)
+            elif display_timezone is not None and key in TIMESTAMP_FIELDS and value:
+                data[key] = _to_display_timezone(value)
+    return data
+
+
+def _is_number(value: Any) -> bool:
+    """Whether a JSON value is numeric (JSON booleans are not)."""
+    return isinstance(value, int | float) and not isinstance(value, bool)
+
+
+def _to_display_timezone(value: Any) -> Any:
+    """Convert an ISO 8601 timestamp into DISPLAY_TIMEZONE, leaving anything else untouched."""
+    if not isinstance(value, str):
+        return value
+    try:
+        parsed = datetime.datetime.fromisoformat(value)
+    except ValueError:
+        return value
+    if parsed.tzinfo is None:
+        return value
+    return parsed.astimezone(display_timezone).isoformat()
+
+
+def _choice_value(field: Any) -> Any:
+    """Return the raw value of a NetBox choice field ({"value", "label"}), or the field itself."""
+    if isinstance(field, dict):
+        return field.get("value")
+    return field
+
+
+def _is_free_interface(interface: dict[str, Any]) -> bool:
+    """Whether an interface is an enabled, uncabled physical port available for a new connection."""
+    return (
+        bool(interface.get("enabled"))
+        and not interface.get("cable")
+        and not interface.get("mark_connected")
+        and _choice_value(interface.get("type")) not in NON_PHYSICAL_INTERFACE_TYPES
+    )
+
+
+def _get_all_objects(
+    endpoint: str,
+    params: dict[str, Any] | None = None,
+    fallback_endpoint: str | None = None,
+) -> list[dict[str, Any]]:
+    """
+    Fetch every object from a list endpoint by following pagination.
+
+    Used by tools that aggregate across a full result set server-side rather than
+    handing pages back to the LLM.
+
+    Args:
+        endpoint: The API endpoint (e.g., 'ipam/prefixes')
+        params: Optional filter parameters applied to every page
+        fallback_endpoint: Optional alternative endpoint to try if primary returns 404
+
+    Returns:
+        List of all objects matching the query
+    """
+    objects: list[dict[str, Any]] = []
+    while True:
+        page_params = {**(params or {}), "limit": 1000, "offset": len(objects)}
+        response = netbox.get(endpoint, params=page_params, fallback_endpoint=fallback_endpoint)
+        results = response.get("results", [])
+        objects.extend(results)
+        # Offset tracks what was returned: NetBox may cap limit below what we asked for
+        if not response.get("next") or not results:
+            return objects
+
+
+def _validate_object_type(object_type: str) -> None:
+    """
+    Raise ValueError, listing the valid choices, unless object_type is a known NetBox type.
+
+    Args:
+        object_type: The NetBox object type (e.g., "dcim.device")
+    """
+    if object_type not in NETBOX_OBJECT_TYPES:
+        valid_types = "\n".join(f"- {t}" for t in sorted(NETBOX_OBJECT_TYPES.keys()))
+        raise ValueError(f"Invalid object_type '{object_type}'. Must be one of:\n{valid_types}")
+
+
+def _get_endpoint_info(object_type: str) -> tuple[str, str | None]:
+    """
+    Returns (endpoint, fallback_endpoint) for the given object type.
+
+    The fallback_endpoint is used for NetBox version compatibility when
+    an endpoint path has changed between versions.
+
+    Args:
+        object_type: The NetBox object type (e.g., "dcim.device")
+
+    Returns:
+        Tuple of (endpoint, fallback_endpoint). fallback_endpoint is None
+        if no fallback is needed for this object type.
+    """
+    type_info = NETBOX_OBJECT_TYPES[object_type]
+    return type_info["endpoint"], type_info.get("fallback_endpoint")
+
+
+@mcp.custom_route("/healthz", methods=["GET"])
+async def healthz(request: Request) -> JSONResponse:
+    """Liveness probe: the server process is up and serving HTTP."""
+    return JSONResponse({"status": "ok"})
+
+
+@mcp.custom_route("/readyz", methods=["GET"])
+async def readyz(request: Request) -> JSONResponse:
+    """Readiness probe: NetBox is reachable and accepts the configured token (never cached)."""
+    try:
+        await asyncio.to_thread(netbox.get, "status", use_cache=False)
+    except Exception as e:
+        category = classify_error(e)["category"]
+        logging.getLogger(__name__).warning(f"Readiness check failed ({category}): {e}")
+        return JSONResponse({"status": "unavailable", "reason": category}, status_code=503)
+    return JSONResponse({"status": "ready"})
+
+
+def load_saved_queries(path: str) -> dict[str, dict[str, Any]]:
+    """Load and validate the saved query library from a JSON file.
+
+    The file maps query names to {"object_type", "filters", "fields", "ordering",
+    "description"}; filter values may contain {placeholders} supplied at run time.
+    Call after plugin discovery so plugin object types are accepted.
+
+    Args:
+        path: Path to the JSON file
+
+    Returns:
+        Dict mapping query names to their definitions
+
+    Raises:
+        ValueError: If the file cannot be read or a query is invalid
+    """
+    try:
+        with open(path, encoding="utf-8") as f:
+            queries = json.load(f)
+    except (OSError, json.JSONDecodeError) as e:
+        raise ValueError(f"Cannot load saved queries from {path}: {e}") from e
+    if not isinstance(queries, dict):
+        raise ValueError(f"{path} must contain a JSON object mapping names to queries")
+
+    for name, query in queries.items():
+        if not isinstance(query, dict) or query.get("object_type") not in NETBOX_OBJECT_TYPES:
+            raise ValueError(f"Saved query '{name}' needs a valid object_type")
+        if not isinstance(query.get("filters", {}), dict):
+            raise ValueError(f"Saved query '{name}' filters must be an object")
+        try:
+            validate_filters(query.get("filters", {}))
+        except ValueError as e:
+            raise ValueError(f"Saved query '{name}': {e}") from e
+    return queries
+
+
+def discover_plugin_types(client: NetBoxRestClient) -> dict[str, dict[str, str]]:
+    """Discover plugin object types from NetBox's object-types API.
+
+    Queries the NetBox instance for installed plugin models that have REST API
+    endpoints and returns them in the same format as NETBOX_OBJECT_TYPES.
+
+    Args:
+        client: Initialized NetBox REST API client
+
+    Returns:
+        Dict mapping type keys (e.g. "netbox_dns.zone") to endpoint info dicts.
+        Returns empty dict on any error (graceful degradation).
+    """
+    logger = logging.getLogger(__name__)
+    plugin_types: dict[str, dict[str, str]] = {}
+
+    try:
+        # Paginate through all object types
+        offset = 0
+        limit = 100
+        while True:
+            response = client.get(
+                "core/object-types",
+                params={"limit": limit, "offset": offset},
+                fallback_endpoint="extras/object-types",  # NetBox < 4.4
+            )
+
+            results = response.get("results", [])
+            for obj_type in results:
+                # Only include plugin models with REST API endpoints
+                if not obj_type.get("is_plugin_model", False):
+                    continue
+
+                rest_url = obj_type.get("rest_api_endpoint")
+                if not rest_url:
+                    continue
+
+                app_label = obj_type.get("app_label", "")
                 model = obj_type.get("model", "")
                 if not app_label or not model:
                     continue
@@ -725,9 +4194,106 @@ async def _update_tool_descriptions() -> None:
             tool.description = f"{prefix}\n\n{type_list}{suffix}"
 
 
+def load_tool_overrides(path: str) -> dict[str, dict[str, Any]]:
+    """Load tool description overrides from a JSON file.
+
+    The file maps tool names to any of "description" (replaces the description),
+    "append" (added after it) and "parameters" (parameter name -> hint appended to
+    that parameter's description).
+
+    Args:
+        path: Path to the JSON file
+
+    Returns:
+        Dict mapping tool names to their overrides
+
+    Raises:
+        ValueError: If the file cannot be read or has an unexpected shape
+    """
+    try:
+        with open(path, encoding="utf-8") as f:
+            overrides = json.load(f)
+    except (OSError, json.JSONDecodeError) as e:
+        raise ValueError(f"Cannot load tool overrides from {path}: {e}") from e
+    if not isinstance(overrides, dict):
+        raise ValueError(f"{path} must contain a JSON object mapping tool names to overrides")
+
+    for name, override in overrides.items():
+        if not isinstance(override, dict):
+            raise ValueError(f"Overrides for '{name}' must be an object")
+        unknown = set(override) - {"description", "append", "parameters"}
+        if unknown:
+            raise ValueError(f"Unknown override keys for '{name}': {', '.join(sorted(unknown))}")
+        if not isinstance(override.get("parameters", {}), dict):
+            raise ValueError(f"Parameter hints for '{name}' must be an object")
+    return overrides
+
+
+async def _apply_tool_overrides(overrides: dict[str, dict[str, Any]]) -> None:
+    """Rewrite tool and parameter descriptions as configured in TOOL_OVERRIDES_FILE.
+
+    Lets operators add house rules (e.g. "always filter by tenant") to the generic
+    tools without forking. Every tool and parameter name is checked so a typo fails
+    startup rather than silently dropping a rule.
+
+    Args:
+        overrides: Overrides as returned by load_tool_overrides
+
+    Raises:
+        ValueError: If a tool or parameter name does not exist
+    """
+    for name, override in overrides.items():
+        try:
+            tool = await mcp.get_tool(name)
+        except NotFoundError:
+            tool = None
+        if tool is None:
+            raise ValueError(f"Unknown tool '{name}' in TOOL_OVERRIDES_FILE")
+
+        properties = tool.parameters.get("properties", {})
+        for param, hint in override.get("parameters", {}).items():
+            if param not in properties:
+                raise ValueError(f"Tool '{name}' has no parameter '{param}'")
+            existing = properties[param].get("description")
+            properties[param]["description"] = f"{existing}\n\n{hint}" if existing else hint
+
+        if "description" in override:
+            tool.description = override["description"]
+        if "append" in override:
+            tool.description = f"{tool.description or ''}\n\n{override['append']}".lstrip()
+
+
+async def _apply_tool_selection(enabled_tools: list[str], disabled_tools: list[str]) -> None:
+    """Hide tools according to the ENABLED_TOOLS and DISABLED_TOOLS settings.
+
+    An allowlist hides every tool not named in it; the denylist is applied
+    afterwards. Names are checked first so a typo fails startup instead of
+    silently exposing a tool the operator meant to hide.
+
+    Args:
+        enabled_tools: Tool names to expose (empty means all tools)
+        disabled_tools: Tool names to hide
+
+    Raises:
+        ValueError: If a name does not match a registered tool
+    """
+    for name in (*enabled_tools, *disabled_tools):
+        try:
+            tool = await mcp.get_tool(name)
+        except NotFoundError:
+            tool = None
+        if tool is None:
+            raise ValueError(f"Unknown tool '{name}' in ENABLED_TOOLS/DISABLED_TOOLS")
+
+    if enabled_tools:
+        mcp.enable(names=set(enabled_tools), only=True)
+    if disabled_tools:
+        mcp.disable(names=set(disabled_tools))
+
+
 def main() -> None:
     """Main entry point for the MCP server."""
-    global netbox
+    global netbox, normalize_units, display_timezone, saved_queries, max_response_chars
 
     cli_overlay: dict[str, Any] = parse_cli_args()
 
@@ -737,7 +4303,7 @@ def main() -> None:
         print(f"Configuration error: {e}", file=sys.stderr)  # noqa: T201 - before logging configured
         sys.exit(1)
 
-    configure_logging(settings.log_level)
+    configure_logging(settings.log_level, settings.log_format)
     logger = logging.getLogger(__name__)
 
     logger.info("Starting NetBox MCP Server")
@@ -769,30 +4335,75 @@ def main() -> None:
             url=str(settings.netbox_url),
             token=settings.netbox_token.get_secret_value(),
             verify_ssl=settings.verify_ssl,
+            max_retries=settings.netbox_max_retries,
+            retry_backoff=settings.netbox_retry_backoff,
+            cache_ttl=settings.netbox_cache_ttl,
+            cache_size=settings.netbox_cache_size,
+            proxy=settings.netbox_proxy,
+            timeout=settings.netbox_timeout,
+            connect_timeout=settings.netbox_connect_timeout,
+            auth_scheme=settings.netbox_auth_scheme,
+            extra_headers=settings.netbox_extra_headers,
         )
         logger.debug("NetBox client initialized successfully")
     except Exception as e:
         logger.error(f"Failed to initialize NetBox client: {e}")
         sys.exit(1)
 
+    normalize_units = settings.normalize_units
+    max_response_chars = settings.max_response_chars
+    if settings.display_timezone:
+        display_timezone = ZoneInfo(settings.display_timezone)
+
     if settings.enable_plugin_discovery:
         plugin_types = discover_plugin_types(netbox)
         if plugin_types:
             NETBOX_OBJECT_TYPES.update(plugin_types)
             asyncio.run(_update_tool_descriptions())
 
+    if settings.saved_queries_file:
+        try:
+            saved_queries = load_saved_queries(settings.saved_queries_file)
+        except ValueError as e:
+            logger.error(str(e))
+            sys.exit(1)
+        logger.info(f"Loaded {len(saved_queries)} saved queries")
+
+    if settings.tool_overrides_file:
+        try:
+            asyncio.run(_apply_tool_overrides(load_tool_overrides(settings.tool_overrides_file)))
+        except ValueError as e:
+            logger.error(f"Invalid tool overrides: {e}")
+            sys.exit(1)
+
+    if settings.enabled_tools or settings.disabled_tools:
+        try:
+            asyncio.run(_apply_tool_selection(settings.enabled_tools, settings.disabled_tools))
+        except ValueError as e:
+            logger.error(f"Invalid tool selection: {e}")
+            sys.exit(1)
+
     try:
         if settings.transport == "stdio":
             logger.info("Starting stdio transport")
             mcp.run(transport="stdio")
         elif settings.transport == "http":
             logger.info(f"Starting HTTP transport on {settings.host}:{settings.port}")
+            oauth = build_oauth_auth(settings)
             auth = build_http_auth(settings.mcp_auth_token)
-            if auth is not None:
+            if oauth is not None:
+                mcp.auth = oauth
+                logger.info(
+                    f"HTTP transport authentication enabled (OAuth, issuer "
+                    f"{settings.mcp_oauth_issuer})"
+                )
+            elif auth is not None:
                 # FastMCP reads mcp.auth when it builds the HTTP app at run time,
                 # so this assignment wires it (the 401 tests verify enforcement).
                 mcp.auth = auth
                 logger.info("HTTP transport authentication enabled (bearer token required)")
+            elif settings.tls_client_ca_file:
+                logger.info("HTTP transport authentication enabled (client certificate required)")
             else:
                 logger.warning(
                     "HTTP transport is running without authentication. Set "
@@ -812,7 +4423,16 @@ def main() -> None:
                     expose_headers=["mcp-session-id"],
                 )
             ]
-            mcp.run(transport="http", host=settings.host, port=settings.port, middleware=middleware)
+            tls_config = build_tls_config(settings)
+            if tls_config:
+                logger.info("Serving HTTP transport over TLS")
+            mcp.run(
+                transport="http",
+                host=settings.host,
+                port=settings.port,
+                middleware=middleware,
+                uvicorn_config=tls_config or None,
+            )
     except Exception as e:
         logger.error(f"Failed to start MCP server: {e}")
         sys.exit(1)
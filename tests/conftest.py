@@ -0,0 +1,40 @@
+"""Shared helpers for tests that stand in for the NetBox client."""
+
+from collections.abc import Callable
+from typing import Any
+
+
+def paged(results: list[dict[str, Any]]) -> dict[str, Any]:
+    """Wrap results in a single-page NetBox list response."""
+    return {"count": len(results), "next": None, "previous": None, "results": results}
+
+
+def fake_netbox_get(responses: dict[str, Any], missing_ok: bool = False) -> Callable[..., Any]:
+    """
+    Build a side effect for a patched netbox.get that answers by endpoint.
+
+    Each value in responses is one of:
+        - a list of objects: returned as a single page, or the object with the
+          requested id when get() is called with id
+        - a dict: returned as is (a single object, or a hand-built list response)
+        - a callable: called with id and params, for endpoints whose answer
+          depends on the query
+
+    Requests to any other endpoint fail the test, or get an empty page with missing_ok.
+    """
+
+    def get(endpoint, id=None, params=None, fallback_endpoint=None, use_cache=True):
+        if endpoint not in responses:
+            if missing_ok:
+                return paged([])
+            raise AssertionError(f"Unexpected endpoint {endpoint}")
+        response = responses[endpoint]
+        if callable(response):
+            return response(id=id, params=params or {})
+        if isinstance(response, dict):
+            return response
+        if id is not None:
+            return next(obj for obj in response if obj["id"] == id)
+        return paged(response)
+
+    return get
@@ -0,0 +1,61 @@
+"""Tests for the group-by aggregation tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_aggregate_objects
+
+
+def _device(site, role, status="active"):
+    return {
+        "site": {"id": 1, "display": site},
+        "role": {"id": 2, "display": role} if role else None,
+        "status": {"value": status, "label": status.title()},
+    }
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_counts_per_nested_group_largest_first(mock_netbox):
+    """Objects should be counted per (site, role), largest group first."""
+    devices = [
+        _device("DC1", "leaf"),
+        _device("DC1", "leaf"),
+        _device("DC1", "spine"),
+        _device("DC2", "leaf"),
+        _device("DC2", None),
+        _device("DC2", "leaf"),
+        _device("DC2", "leaf"),
+    ]
+    mock_netbox.get.return_value = {"count": 7, "next": None, "results": devices}
+
+    result = netbox_aggregate_objects("dcim.device", {"status": "active"}, ["site", "role"])
+
+    params = mock_netbox.get.call_args.kwargs["params"]
+    assert params["fields"] == "site,role"
+    assert params["status"] == "active"
+    assert result["total"] == 7
+    assert result["group_count"] == 4
+    assert result["groups"][:2] == [
+        {"site": "DC2", "role": "leaf", "count": 3},
+        {"site": "DC1", "role": "leaf", "count": 2},
+    ]
+    assert {"site": "DC2", "role": None, "count": 1} in result["groups"]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_choice_fields_group_by_value_and_limit_applies(mock_netbox):
+    devices = [_device("DC1", "leaf", "active")] * 3 + [_device("DC1", "leaf", "planned")]
+    mock_netbox.get.return_value = {"count": 4, "next": None, "results": devices}
+
+    result = netbox_aggregate_objects("dcim.device", {}, "status", limit=1)
+
+    assert result["group_count"] == 2
+    assert result["groups"] == [{"status": "active", "count": 3}]
+
+
+def test_rejects_invalid_filters_and_empty_group_by():
+    with pytest.raises(ValueError):
+        netbox_aggregate_objects("dcim.device", {"device__site_id": 1}, "role")
+    with pytest.raises(ValueError):
+        netbox_aggregate_objects("dcim.device", {}, [])
@@ -0,0 +1,71 @@
+"""Tests for the ASN usage cross-reference tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_get_asn_usage
+from tests.conftest import fake_netbox_get, paged
+
+
+ASN_RECORD = {
+    "id": 4,
+    "asn": 65001,
+    "display": "AS65001",
+    "rir": {"display": "RFC 6996"},
+    "tenant": None,
+    "description": "Edge",
+    "custom_fields": {"bgp_community": "65001:100", "peering_policy": None},
+}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_unknown_asn_raises(mock_netbox):
+    """An ASN number with no matching record should raise a clear error."""
+    mock_netbox.get.side_effect = fake_netbox_get({}, missing_ok=True)
+
+    with pytest.raises(ValueError, match="ASN 65099 not found"):
+        netbox_get_asn_usage(asn=65099)
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_collects_sites_providers_and_custom_fields(mock_netbox):
+    """Sites and providers should be looked up by ASN ID, with empty custom fields dropped."""
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {
+            "ipam/asns": [ASN_RECORD],
+            "dcim/sites": [{"id": 1, "display": "DC1"}],
+            "circuits/providers": [{"id": 2, "display": "Transit Co"}],
+        }
+    )
+
+    result = netbox_get_asn_usage(asn=65001)
+
+    assert result["asn"]["rir"] == "RFC 6996"
+    assert result["custom_fields"] == {"bgp_community": "65001:100"}
+    assert result["sites"] == [{"id": 1, "display": "DC1"}]
+    assert result["providers"] == [{"id": 2, "display": "Transit Co"}]
+    assert result["devices"] == []
+    site_call = mock_netbox.get.call_args_list[1]
+    assert site_call[0][0] == "dcim/sites"
+    assert site_call[1]["params"]["asn_id"] == 4
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_devices_matched_by_custom_field(mock_netbox):
+    """With device_custom_field set, devices should be filtered on that field's ASN value."""
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {
+            "ipam/asns": [ASN_RECORD],
+            "dcim/sites": [],
+            "circuits/providers": [],
+            "dcim/devices": [{"id": 7, "display": "edge1", "site": {"display": "DC1"}}],
+        }
+    )
+
+    result = netbox_get_asn_usage(asn=65001, device_custom_field="bgp_asn")
+
+    assert result["devices"] == [{"id": 7, "display": "edge1", "site": "DC1"}]
+    device_call = mock_netbox.get.call_args_list[-1]
+    assert device_call[0][0] == "dcim/devices"
+    assert device_call[1]["params"]["cf_bgp_asn"] == 65001
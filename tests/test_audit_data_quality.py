@@ -0,0 +1,62 @@
+"""Tests for the data-quality audit tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_audit_data_quality
+
+
+def _fake_get(counts):
+    def get(endpoint, params=None, fallback_endpoint=None):
+        key = (endpoint, next(k for k in params if k not in ("fields", "limit", "site_id")))
+        count = counts.get(key, 0)
+        results = [{"id": i, "display": f"{endpoint}-{i}"} for i in range(min(count, 2))]
+        return {"count": count, "next": None, "results": results}
+
+    return get
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_runs_all_checks_and_orders_by_priority(mock_netbox):
+    """Every check should report its full count; issues list medium before low."""
+    mock_netbox.get.side_effect = _fake_get(
+        {
+            ("dcim/devices", "serial__empty"): 12,
+            ("dcim/devices", "has_primary_ip"): 3,
+            ("ipam/ip-addresses", "assigned_to_interface"): 1,
+        }
+    )
+
+    result = netbox_audit_data_quality()
+
+    assert result["summary"] == {
+        "device_without_primary_ip": 3,
+        "vm_without_primary_ip": 0,
+        "device_without_serial": 12,
+        "device_without_platform": 0,
+        "interface_type_other": 0,
+        "ip_without_assignment": 1,
+    }
+    assert [issue["priority"] for issue in result["issues"]] == ["medium"] * 2 + ["low"] * 3
+    assert result["issues"][0]["check"] == "device_without_primary_ip"
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_selected_checks_and_site_filter(mock_netbox):
+    """Only selected checks run, and site_id is applied where the type supports it."""
+    mock_netbox.get.side_effect = _fake_get({})
+
+    result = netbox_audit_data_quality(
+        checks=["device_without_serial", "ip_without_assignment"], site_id=4
+    )
+
+    assert list(result["summary"]) == ["device_without_serial", "ip_without_assignment"]
+    device_params, ip_params = (call.kwargs["params"] for call in mock_netbox.get.call_args_list)
+    assert device_params["site_id"] == 4
+    assert "site_id" not in ip_params
+
+
+def test_unknown_check_rejected():
+    with pytest.raises(ValueError, match="Unknown checks"):
+        netbox_audit_data_quality(checks=["racks_without_site"])
@@ -0,0 +1,132 @@
+"""Tests for the prefix/VLAN consistency audit tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_audit_prefix_vlan_consistency
+from tests.conftest import fake_netbox_get, paged
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_clean_data_reports_no_issues(mock_netbox):
+    """Prefixes with roles and matching VLAN sites should produce no issues."""
+    prefixes = [
+        {
+            "id": 1,
+            "display": "10.0.0.0/24",
+            "role": {"id": 1},
+            "vlan": {"id": 10, "display": "VLAN 10"},
+            "scope_type": "dcim.site",
+            "scope_id": 5,
+        }
+    ]
+    vlans = [{"id": 10, "display": "VLAN 10", "site": {"id": 5}, "tags": []}]
+    mock_netbox.get.side_effect = fake_netbox_get({"ipam/prefixes": prefixes, "ipam/vlans": vlans})
+
+    result = netbox_audit_prefix_vlan_consistency()
+
+    assert result["issues"] == []
+    assert all(count == 0 for count in result["summary"].values())
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_flags_each_check_in_priority_order(mock_netbox):
+    """Every check should be detected and issues sorted high -> medium -> low."""
+    prefixes = [
+        {
+            "id": 1,
+            "display": "10.0.0.0/24",
+            "role": None,
+            "vlan": {"id": 10, "display": "VLAN 10"},
+            "scope_type": "dcim.site",
+            "scope_id": 6,
+        }
+    ]
+    vlans = [
+        {"id": 10, "display": "VLAN 10", "site": {"id": 5}, "tags": []},
+        {"id": 20, "display": "VLAN 20", "site": None, "tags": []},
+    ]
+    mock_netbox.get.side_effect = fake_netbox_get({"ipam/prefixes": prefixes, "ipam/vlans": vlans})
+
+    result = netbox_audit_prefix_vlan_consistency()
+
+    assert result["summary"] == {
+        "prefix_vlan_site_mismatch": 1,
+        "vlan_without_site_or_tags": 1,
+        "vlan_without_prefix": 1,
+        "prefix_without_role": 1,
+    }
+    priorities = [issue["priority"] for issue in result["issues"]]
+    assert priorities == ["high", "medium", "medium", "low"]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_limit_truncates_issues_but_not_summary(mock_netbox):
+    """The limit caps the issue list while the summary keeps full counts."""
+    prefixes = [{"id": i, "display": f"10.0.{i}.0/24", "role": None} for i in range(10)]
+    mock_netbox.get.side_effect = fake_netbox_get({"ipam/prefixes": prefixes, "ipam/vlans": []})
+
+    result = netbox_audit_prefix_vlan_consistency(limit=3)
+
+    assert len(result["issues"]) == 3
+    assert result["summary"]["prefix_without_role"] == 10
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_site_id_scopes_both_queries(mock_netbox):
+    """site_id should be passed as a filter to both prefix and VLAN queries."""
+    mock_netbox.get.side_effect = fake_netbox_get({"ipam/prefixes": [], "ipam/vlans": []})
+
+    netbox_audit_prefix_vlan_consistency(site_id=7)
+
+    for call in mock_netbox.get.call_args_list:
+        assert call[1]["params"]["site_id"] == 7
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_site_id_still_checks_against_other_sites(mock_netbox):
+    """A site's prefix using another site's VLAN is flagged; VLANs used elsewhere are not."""
+    local_prefix = {
+        "id": 1,
+        "display": "10.7.0.0/24",
+        "role": {"id": 1},
+        "vlan": {"id": 30, "display": "VLAN 30"},
+        "scope_type": "dcim.site",
+        "scope_id": 7,
+    }
+    remote_prefix = {"vlan": {"id": 40}}
+    local_vlan = {"id": 40, "display": "VLAN 40", "site": {"id": 7}, "tags": []}
+    remote_vlan = {"id": 30, "site": {"id": 8}}
+
+    def prefixes(id, params):
+        return paged([remote_prefix] if "vlan_id" in params else [local_prefix])
+
+    def vlans(id, params):
+        return paged([local_vlan] if "site_id" in params else [remote_vlan])
+
+    mock_netbox.get.side_effect = fake_netbox_get({"ipam/prefixes": prefixes, "ipam/vlans": vlans})
+
+    result = netbox_audit_prefix_vlan_consistency(site_id=7)
+
+    assert result["summary"]["prefix_vlan_site_mismatch"] == 1
+    assert result["summary"]["vlan_without_prefix"] == 0
+    params = [call.kwargs["params"] for call in mock_netbox.get.call_args_list]
+    assert {"id": [30], "fields": "id,site"}.items() <= params[2].items()
+    assert params[3]["vlan_id"] == [40]
+    assert "site_id" not in params[2] and "site_id" not in params[3]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_follows_pagination(mock_netbox):
+    """All pages should be fetched, advancing offset by the page size returned."""
+    pages = [
+        {"count": 3, "next": "page2", "results": [{"id": 1, "role": {"id": 1}}]},
+        {"count": 3, "next": None, "results": [{"id": 2, "role": None}]},
+        paged([]),
+    ]
+    mock_netbox.get.side_effect = pages
+
+    result = netbox_audit_prefix_vlan_consistency()
+
+    assert result["summary"]["prefix_without_role"] == 1
+    offsets = [call[1]["params"]["offset"] for call in mock_netbox.get.call_args_list]
+    assert offsets[:2] == [0, 1]
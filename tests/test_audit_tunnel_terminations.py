@@ -0,0 +1,70 @@
+"""Tests for the tunnel termination outside IP audit tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_audit_tunnel_terminations
+from tests.conftest import fake_netbox_get
+
+
+def _termination(termination_id, interface_id, outside_ip=None):
+    return {
+        "id": termination_id,
+        "display": f"Termination {termination_id}",
+        "tunnel": {"display": "tun0"},
+        "termination_type": "dcim.interface",
+        "termination_id": interface_id,
+        "termination": {"display": f"eth{interface_id}"},
+        "outside_ip": outside_ip,
+    }
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_matching_outside_ip_reports_no_issues(mock_netbox):
+    """An outside IP assigned to the terminating interface is consistent."""
+    terminations = [_termination(1, 10, {"id": 100, "address": "192.0.2.1/32"})]
+    ips = [{"id": 100, "assigned_object_type": "dcim.interface", "assigned_object_id": 10}]
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"vpn/tunnel-terminations": terminations, "ipam/ip-addresses": ips}
+    )
+
+    result = netbox_audit_tunnel_terminations()
+
+    assert result["issues"] == []
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_flags_mismatched_and_missing_outside_ips(mock_netbox):
+    """Mismatched IPs should be high priority and missing IPs medium."""
+    terminations = [
+        _termination(1, 10),
+        _termination(2, 20, {"id": 200, "address": "192.0.2.2/32"}),
+        _termination(3, 30, {"id": 300, "address": "192.0.2.3/32"}),
+    ]
+    ips = [
+        {"id": 200, "assigned_object_type": "dcim.interface", "assigned_object_id": 99},
+        {"id": 300, "assigned_object_type": None, "assigned_object_id": None},
+    ]
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"vpn/tunnel-terminations": terminations, "ipam/ip-addresses": ips}
+    )
+
+    result = netbox_audit_tunnel_terminations()
+
+    assert result["summary"] == {
+        "outside_ip_not_on_interface": 2,
+        "termination_without_outside_ip": 1,
+    }
+    assert [issue["id"] for issue in result["issues"]] == [2, 3, 1]
+    assert "192.0.2.2/32" in result["issues"][0]["detail"]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_tunnel_id_filters_terminations(mock_netbox):
+    """tunnel_id should be passed through to the terminations query."""
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"vpn/tunnel-terminations": [], "ipam/ip-addresses": []}
+    )
+
+    netbox_audit_tunnel_terminations(tunnel_id=4)
+
+    assert mock_netbox.get.call_args[1]["params"]["tunnel_id"] == 4
@@ -0,0 +1,70 @@
+"""Tests for the available-IP/prefix/VLAN lookup tools."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import (
+    netbox_get_available_ips,
+    netbox_get_available_prefixes,
+    netbox_get_available_vlans,
+)
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_available_ips_calls_prefix_child_endpoint(mock_netbox):
+    """Available IPs should be read from the prefix's available-ips endpoint."""
+    mock_netbox.get.return_value = [{"family": 4, "address": "10.0.0.1/24", "vrf": None}]
+
+    result = netbox_get_available_ips(prefix_id=42, limit=3)
+
+    mock_netbox.get.assert_called_once_with("ipam/prefixes/42/available-ips", params={"limit": 3})
+    assert result == [{"family": 4, "address": "10.0.0.1/24", "vrf": None}]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_available_prefixes_returns_all_blocks_without_length(mock_netbox):
+    """Without prefix_length every free block should be returned unchanged."""
+    blocks = [
+        {"family": 4, "prefix": "10.0.0.128/25", "vrf": None},
+        {"family": 4, "prefix": "10.0.0.64/27", "vrf": None},
+    ]
+    mock_netbox.get.return_value = blocks
+
+    result = netbox_get_available_prefixes(prefix_id=7)
+
+    mock_netbox.get.assert_called_once_with("ipam/prefixes/7/available-prefixes")
+    assert result == blocks
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_available_prefixes_filters_blocks_too_small(mock_netbox):
+    """Only blocks able to hold a child of the requested length should remain."""
+    mock_netbox.get.return_value = [
+        {"family": 4, "prefix": "10.0.0.128/25", "vrf": None},
+        {"family": 4, "prefix": "10.0.0.64/27", "vrf": None},
+        {"family": 4, "prefix": "10.0.0.0/26", "vrf": None},
+    ]
+
+    result = netbox_get_available_prefixes(prefix_id=7, prefix_length=26)
+
+    assert [block["prefix"] for block in result] == ["10.0.0.128/25", "10.0.0.0/26"]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_available_prefixes_rejects_length_beyond_family(mock_netbox):
+    """An IPv6-sized length should not match IPv4 blocks."""
+    mock_netbox.get.return_value = [{"family": 4, "prefix": "10.0.0.0/24", "vrf": None}]
+
+    assert netbox_get_available_prefixes(prefix_id=7, prefix_length=64) == []
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_available_vlans_calls_vlan_group_child_endpoint(mock_netbox):
+    """Available VLANs should be read from the group's available-vlans endpoint."""
+    mock_netbox.get.return_value = [{"vid": 101, "group": {"id": 3}}]
+
+    result = netbox_get_available_vlans(vlan_group_id=3, limit=1)
+
+    mock_netbox.get.assert_called_once_with(
+        "ipam/vlan-groups/3/available-vlans", params={"limit": 1}
+    )
+    assert result == [{"vid": 101, "group": {"id": 3}}]
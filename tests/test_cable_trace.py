@@ -0,0 +1,52 @@
+"""Tests for the cable path trace tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_trace_cable_path
+
+
+def test_untraceable_object_type_rejected():
+    """Object types without a trace endpoint should be rejected with the valid list."""
+    with pytest.raises(ValueError, match="does not support cable tracing"):
+        netbox_trace_cable_path(object_type="dcim.device", object_id=1)
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_interface_trace_returns_hops(mock_netbox):
+    """Interface traces should be summarized into near_end/cable/far_end hops."""
+    mock_netbox.get.return_value = [
+        [
+            [{"id": 1, "display": "eth0", "device": {"display": "leaf1"}, "url": "..."}],
+            {"id": 9, "display": "#9", "label": "A-9", "url": "..."},
+            [{"id": 5, "display": "Front1", "device": {"display": "patch1"}}],
+        ],
+        [
+            [{"id": 6, "display": "Rear1", "device": {"display": "patch1"}}],
+            None,
+            [],
+        ],
+    ]
+
+    result = netbox_trace_cable_path(object_type="dcim.interface", object_id=1)
+
+    mock_netbox.get.assert_called_once_with("dcim/interfaces/1/trace")
+    first, second = result["hops"]
+    assert first["near_end"] == [{"id": 1, "display": "eth0", "parent": "leaf1"}]
+    assert first["cable"] == {"id": 9, "display": "#9", "label": "A-9"}
+    assert first["far_end"] == [{"id": 5, "display": "Front1", "parent": "patch1"}]
+    assert second["cable"] is None
+    assert second["far_end"] == []
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_front_port_uses_paths_endpoint(mock_netbox):
+    """Pass-through ports should return every path from the paths endpoint."""
+    paths = [{"id": 3, "origin": [], "destination": [], "path": [], "is_active": True}]
+    mock_netbox.get.return_value = paths
+
+    result = netbox_trace_cable_path(object_type="dcim.frontport", object_id=4)
+
+    mock_netbox.get.assert_called_once_with("dcim/front-ports/4/paths")
+    assert result == {"paths": paths}
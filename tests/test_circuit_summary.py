@@ -0,0 +1,76 @@
+"""Tests for the circuit summary tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_get_circuit_summary
+from tests.conftest import fake_netbox_get
+
+CIRCUIT = {
+    "id": 4,
+    "cid": "CID-1001",
+    "status": {"value": "active", "label": "Active"},
+    "type": {"id": 1, "display": "Internet"},
+    "provider": {"id": 2, "display": "Acme Transit"},
+    "provider_account": {"id": 3, "display": "ACCT-9"},
+    "commit_rate": 1000000,
+    "tenant": None,
+}
+
+TERMINATIONS = [
+    {
+        "id": 10,
+        "term_side": "A",
+        "termination_type": "dcim.site",
+        "termination": {"id": 1, "display": "DC1"},
+        "port_speed": 10000000,
+        "upstream_speed": None,
+        "xconnect_id": "XC-55",
+        "pp_info": "MMR panel 3, port 12",
+        "cable": {"id": 8, "display": "#8"},
+        "link_peers_type": "dcim.interface",
+        "link_peers": [{"id": 20, "name": "xe-0/0/0", "device": {"id": 5, "display": "edge-01"}}],
+    },
+    {
+        "id": 11,
+        "term_side": "Z",
+        "site": None,
+        "provider_network": {"id": 6, "display": "Acme Backbone"},
+        "port_speed": None,
+        "upstream_speed": None,
+        "xconnect_id": "",
+        "pp_info": "",
+        "cable": None,
+        "link_peers_type": None,
+        "link_peers": [],
+    },
+]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_denormalizes_circuit_and_terminations(mock_netbox):
+    """Both sides should be resolved, including pre-4.2 provider network terminations."""
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"circuits/circuits": CIRCUIT, "circuits/circuit-terminations": TERMINATIONS}
+    )
+
+    result = netbox_get_circuit_summary(circuit_id=4)
+
+    assert result["cid"] == "CID-1001"
+    assert result["provider"] == "Acme Transit"
+    assert result["provider_account"] == "ACCT-9"
+    assert result["terminations"]["A"] == {
+        "termination_type": "dcim.site",
+        "termination": "DC1",
+        "port_speed": 10000000,
+        "upstream_speed": None,
+        "xconnect_id": "XC-55",
+        "pp_info": "MMR panel 3, port 12",
+        "cable": "#8",
+        "connected_to": [{"type": "dcim.interface", "name": "xe-0/0/0", "device": "edge-01"}],
+    }
+    z_side = result["terminations"]["Z"]
+    assert z_side["termination_type"] == "circuits.providernetwork"
+    assert z_side["termination"] == "Acme Backbone"
+    assert z_side["connected_to"] == []
+    terminations_call = mock_netbox.get.call_args_list[1]
+    assert terminations_call.kwargs["params"]["circuit_id"] == 4
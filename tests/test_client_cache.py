@@ -0,0 +1,109 @@
+"""Tests for the NetBoxRestClient in-memory response cache."""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from netbox_mcp_server.netbox_client import NetBoxRestClient
+
+
+def _response(json_data):
+    response = MagicMock()
+    response.status_code = 200
+    response.json.return_value = json_data
+    return response
+
+
+@pytest.fixture
+def client():
+    """Create a test client with caching enabled."""
+    return NetBoxRestClient(
+        url="https://netbox.example.com",
+        token="test-token",
+        cache_ttl=30,
+        cache_size=2,
+    )
+
+
+def test_cache_disabled_by_default():
+    """Without cache_ttl, every call should reach NetBox."""
+    client = NetBoxRestClient(url="https://netbox.example.com", token="test-token")
+    with patch.object(client.session, "get") as mock_get:
+        mock_get.return_value = _response({"id": 1})
+
+        client.get("dcim/sites", id=1)
+        client.get("dcim/sites", id=1)
+
+    assert mock_get.call_count == 2
+
+
+def test_repeated_request_served_from_cache(client):
+    """Identical requests within the TTL should make a single round trip."""
+    with patch.object(client.session, "get") as mock_get:
+        mock_get.return_value = _response({"count": 1, "results": [{"id": 1}]})
+
+        first = client.get("dcim/sites", params={"status": "active", "limit": 5})
+        second = client.get("dcim/sites", params={"limit": 5, "status": "active"})
+
+    assert mock_get.call_count == 1
+    assert first == second
+
+
+def test_different_params_not_shared(client):
+    """Requests differing in params should be cached separately."""
+    with patch.object(client.session, "get") as mock_get:
+        mock_get.return_value = _response({"count": 0, "results": []})
+
+        client.get("dcim/sites", params={"status": "active"})
+        client.get("dcim/sites", params={"status": "planned"})
+
+    assert mock_get.call_count == 2
+
+
+def test_entries_expire_after_ttl(client):
+    """Once the TTL has passed, the next request should go to NetBox again."""
+    with (
+        patch.object(client.session, "get") as mock_get,
+        patch("netbox_mcp_server.netbox_client.time.monotonic") as mock_clock,
+    ):
+        mock_get.return_value = _response({"id": 1})
+        mock_clock.return_value = 100.0
+        client.get("dcim/sites", id=1)
+
+        mock_clock.return_value = 131.0
+        client.get("dcim/sites", id=1)
+
+    assert mock_get.call_count == 2
+
+
+def test_least_recently_used_entry_evicted(client):
+    """Beyond cache_size, the least recently used entry should be dropped."""
+    with patch.object(client.session, "get") as mock_get:
+        mock_get.return_value = _response({"id": 1})
+
+        client.get("dcim/sites", id=1)
+        client.get("dcim/sites", id=2)
+        client.get("dcim/sites", id=1)
+        client.get("dcim/sites", id=3)
+        client.get("dcim/sites", id=1)
+        client.get("dcim/sites", id=2)
+
+    requested = [call[0][0] for call in mock_get.call_args_list]
+    assert requested == [
+        "https://netbox.example.com/api/dcim/sites/1/",
+        "https://netbox.example.com/api/dcim/sites/2/",
+        "https://netbox.example.com/api/dcim/sites/3/",
+        "https://netbox.example.com/api/dcim/sites/2/",
+    ]
+
+
+def test_cached_response_is_isolated_from_caller_mutation(client):
+    """Mutating a returned response must not corrupt the cached copy."""
+    with patch.object(client.session, "get") as mock_get:
+        mock_get.return_value = _response({"id": 1, "tags": []})
+
+        first = client.get("dcim/sites", id=1)
+        first["tags"].append("changed")
+        second = client.get("dcim/sites", id=1)
+
+    assert second == {"id": 1, "tags": []}
@@ -0,0 +1,78 @@
+"""Tests for forwarding server log records to the MCP client."""
+
+import asyncio
+import logging
+from unittest.mock import AsyncMock, MagicMock
+
+import pytest
+
+from netbox_mcp_server.server import ClientLogForwardingMiddleware
+
+logger = logging.getLogger("netbox_mcp_server.netbox_client")
+
+
+@pytest.fixture
+def middleware():
+    mw = ClientLogForwardingMiddleware()
+    logger.setLevel(logging.DEBUG)
+    yield mw
+    logger.setLevel(logging.NOTSET)
+
+
+def _context():
+    context = MagicMock()
+    context.fastmcp_context.log = AsyncMock()
+    return context
+
+
+def test_records_logged_during_call_are_forwarded(middleware):
+    async def call_next(context):
+        logger.debug("NetBox GET dcim/sites returned 200")
+        logger.warning("NetBox returned 503, retrying")
+        return "ok"
+
+    context = _context()
+    result = asyncio.run(middleware.on_call_tool(context, call_next))
+
+    assert result == "ok"
+    calls = context.fastmcp_context.log.call_args_list
+    assert [(c[0][0], c[1]["level"]) for c in calls] == [
+        ("NetBox GET dcim/sites returned 200", "debug"),
+        ("NetBox returned 503, retrying", "warning"),
+    ]
+    assert calls[0][1]["logger_name"] == "netbox_mcp_server.netbox_client"
+
+
+def test_records_forwarded_when_tool_fails(middleware):
+    async def call_next(context):
+        logger.error("NetBox unreachable")
+        raise RuntimeError("boom")
+
+    context = _context()
+    with pytest.raises(RuntimeError):
+        asyncio.run(middleware.on_call_tool(context, call_next))
+
+    context.fastmcp_context.log.assert_awaited_once()
+
+
+def test_logs_outside_tool_calls_are_not_forwarded(middleware):
+    logger.warning("startup message")
+
+    async def call_next(context):
+        return None
+
+    context = _context()
+    asyncio.run(middleware.on_call_tool(context, call_next))
+
+    context.fastmcp_context.log.assert_not_awaited()
+
+
+def test_notification_failure_does_not_fail_the_call(middleware):
+    async def call_next(context):
+        logger.info("Tool done")
+        return "ok"
+
+    context = _context()
+    context.fastmcp_context.log.side_effect = RuntimeError("session closed")
+
+    assert asyncio.run(middleware.on_call_tool(context, call_next)) == "ok"
@@ -0,0 +1,27 @@
+"""Tests for NetBoxRestClient proxy configuration."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.netbox_client import NetBoxRestClient
+
+
+@patch("netbox_mcp_server.netbox_client.httpx.Client")
+def test_environment_proxies_honored_by_default(mock_client):
+    """Without an explicit proxy, the session should read HTTP(S)_PROXY/NO_PROXY."""
+    NetBoxRestClient(url="https://netbox.example.com", token="test-token")
+
+    kwargs = mock_client.call_args[1]
+    assert kwargs["proxy"] is None
+    assert kwargs["trust_env"] is True
+
+
+@patch("netbox_mcp_server.netbox_client.httpx.Client")
+def test_explicit_proxy_passed_to_session(mock_client):
+    """An explicit proxy should be handed to the underlying httpx client."""
+    NetBoxRestClient(
+        url="https://netbox.example.com",
+        token="test-token",
+        proxy="http://proxy.example.com:3128",
+    )
+
+    assert mock_client.call_args[1]["proxy"] == "http://proxy.example.com:3128"
@@ -0,0 +1,127 @@
+"""Tests for NetBoxRestClient retry with exponential backoff.
+
+Reads that fail with rate limiting or transient gateway errors are retried,
+honouring Retry-After, so momentary NetBox hiccups don't fail a tool call.
+"""
+
+from unittest.mock import MagicMock, patch
+
+import httpx
+import pytest
+
+from netbox_mcp_server.netbox_client import NetBoxRestClient
+
+
+@pytest.fixture
+def client():
+    """Create a test client with a small, deterministic retry budget."""
+    return NetBoxRestClient(
+        url="https://netbox.example.com",
+        token="test-token",
+        max_retries=2,
+        retry_backoff=0.5,
+    )
+
+
+def _response(status_code, headers=None, json_data=None):
+    response = MagicMock()
+    response.status_code = status_code
+    response.headers = headers or {}
+    response.json.return_value = json_data
+    if status_code >= 400:
+        response.raise_for_status.side_effect = httpx.HTTPStatusError(
+            f"{status_code}", request=MagicMock(), response=response
+        )
+    return response
+
+
+@pytest.mark.parametrize("status_code", [429, 502, 503, 504])
+def test_transient_status_retried_until_success(client, status_code):
+    """Retryable statuses should be retried and the eventual success returned."""
+    with (
+        patch.object(client.session, "get") as mock_get,
+        patch("netbox_mcp_server.netbox_client.time.sleep") as mock_sleep,
+    ):
+        mock_get.side_effect = [_response(status_code), _response(200, json_data={"id": 1})]
+
+        result = client.get("dcim/sites", id=1)
+
+    assert result == {"id": 1}
+    assert mock_get.call_count == 2
+    mock_sleep.assert_called_once()
+
+
+def test_gives_up_after_max_retries(client):
+    """Once retries are exhausted the last error should be raised."""
+    with (
+        patch.object(client.session, "get") as mock_get,
+        patch("netbox_mcp_server.netbox_client.time.sleep") as mock_sleep,
+    ):
+        mock_get.return_value = _response(503)
+
+        with pytest.raises(httpx.HTTPStatusError):
+            client.get("dcim/sites")
+
+    assert mock_get.call_count == 3
+    assert mock_sleep.call_count == 2
+
+
+def test_non_retryable_status_not_retried(client):
+    """Client errors such as 400 should fail immediately."""
+    with (
+        patch.object(client.session, "get") as mock_get,
+        patch("netbox_mcp_server.netbox_client.time.sleep") as mock_sleep,
+    ):
+        mock_get.return_value = _response(400)
+
+        with pytest.raises(httpx.HTTPStatusError):
+            client.get("dcim/sites")
+
+    assert mock_get.call_count == 1
+    mock_sleep.assert_not_called()
+
+
+def test_retry_after_seconds_honoured(client):
+    """A numeric Retry-After header should set the delay instead of backoff."""
+    with (
+        patch.object(client.session, "get") as mock_get,
+        patch("netbox_mcp_server.netbox_client.time.sleep") as mock_sleep,
+    ):
+        mock_get.side_effect = [
+            _response(429, headers={"Retry-After": "7"}),
+            _response(200, json_data={}),
+        ]
+
+        client.get("dcim/sites")
+
+    mock_sleep.assert_called_once_with(7.0)
+
+
+def test_backoff_is_jittered_and_grows(client):
+    """Without Retry-After, each delay should be drawn from a doubling window."""
+    with (
+        patch.object(client.session, "get") as mock_get,
+        patch("netbox_mcp_server.netbox_client.time.sleep"),
+        patch("netbox_mcp_server.netbox_client.random.uniform", return_value=0.1) as mock_uniform,
+    ):
+        mock_get.side_effect = [_response(502), _response(502), _response(200, json_data={})]
+
+        client.get("dcim/sites")
+
+    assert [c[0] for c in mock_uniform.call_args_list] == [(0, 0.5), (0, 1.0)]
+
+
+def test_zero_retries_disables_retry():
+    """max_retries=0 should send exactly one request."""
+    client = NetBoxRestClient(url="https://netbox.example.com", token="t", max_retries=0)
+    with (
+        patch.object(client.session, "get") as mock_get,
+        patch("netbox_mcp_server.netbox_client.time.sleep") as mock_sleep,
+    ):
+        mock_get.return_value = _response(429)
+
+        with pytest.raises(httpx.HTTPStatusError):
+            client.get("dcim/sites")
+
+    assert mock_get.call_count == 1
+    mock_sleep.assert_not_called()
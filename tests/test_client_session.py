@@ -0,0 +1,50 @@
+"""Tests for NetBoxRestClient HTTP session configuration (timeouts and headers)."""
+
+from unittest.mock import patch
+
+import httpx
+
+from netbox_mcp_server.netbox_client import NetBoxRestClient
+
+
+@patch("netbox_mcp_server.netbox_client.httpx.Client")
+def test_default_timeouts(mock_client):
+    """The session should never wait indefinitely on NetBox."""
+    NetBoxRestClient(url="https://netbox.example.com", token="test-token")
+
+    assert mock_client.call_args[1]["timeout"] == httpx.Timeout(30.0, connect=5.0)
+
+
+@patch("netbox_mcp_server.netbox_client.httpx.Client")
+def test_custom_timeouts(mock_client):
+    """Configured timeouts should be applied to the session."""
+    NetBoxRestClient(
+        url="https://netbox.example.com",
+        token="test-token",
+        timeout=120.0,
+        connect_timeout=2.0,
+    )
+
+    assert mock_client.call_args[1]["timeout"] == httpx.Timeout(120.0, connect=2.0)
+
+
+def test_auth_scheme_follows_token_version():
+    """v2 (nbt_) tokens use Bearer; legacy tokens keep the Token scheme."""
+    legacy = NetBoxRestClient(url="https://netbox.example.com", token="0123abcd")
+    v2 = NetBoxRestClient(url="https://netbox.example.com", token="nbt_abc.def")
+
+    assert legacy.session.headers["Authorization"] == "Token 0123abcd"
+    assert v2.session.headers["Authorization"] == "Bearer nbt_abc.def"
+
+
+def test_explicit_auth_scheme_and_extra_headers():
+    """Hosted NetBox setups can force Bearer and add gateway headers."""
+    client = NetBoxRestClient(
+        url="https://netbox.example.com",
+        token="api-key",
+        auth_scheme="bearer",
+        extra_headers={"X-Tenant": "acme"},
+    )
+
+    assert client.session.headers["Authorization"] == "Bearer api-key"
+    assert client.session.headers["X-Tenant"] == "acme"
@@ -0,0 +1,69 @@
+"""Tests for OpenTelemetry spans around NetBoxRestClient requests."""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from netbox_mcp_server.netbox_client import NetBoxRestClient
+
+
+@pytest.fixture
+def client():
+    """Create a test client."""
+    return NetBoxRestClient(url="https://netbox.example.com", token="test-token")
+
+
+def _response(status_code, json_data=None):
+    response = MagicMock()
+    response.status_code = status_code
+    response.json.return_value = json_data
+    return response
+
+
+def test_get_creates_client_span_with_request_attributes(client):
+    """Each GET should run inside a span named after the endpoint."""
+    with (
+        patch("netbox_mcp_server.netbox_client.tracer") as mock_tracer,
+        patch.object(client.session, "get") as mock_get,
+    ):
+        span = mock_tracer.start_as_current_span.return_value.__enter__.return_value
+        mock_get.return_value = _response(200, {"id": 1})
+
+        client.get("dcim/devices", id=1)
+
+    name = mock_tracer.start_as_current_span.call_args[0][0]
+    attributes = mock_tracer.start_as_current_span.call_args[1]["attributes"]
+    assert name == "NetBox GET dcim/devices"
+    assert attributes["netbox.endpoint"] == "dcim/devices"
+    span.set_attribute.assert_any_call("netbox.object_id", 1)
+    span.set_attribute.assert_any_call("http.response.status_code", 200)
+
+
+def test_span_name_does_not_include_object_ids(client):
+    """IDs embedded in the endpoint belong in attributes, not in the span name."""
+    with (
+        patch("netbox_mcp_server.netbox_client.tracer") as mock_tracer,
+        patch.object(client.session, "get") as mock_get,
+    ):
+        mock_get.return_value = _response(200, [])
+
+        client.get("dcim/interfaces/42/trace")
+
+    name = mock_tracer.start_as_current_span.call_args[0][0]
+    attributes = mock_tracer.start_as_current_span.call_args[1]["attributes"]
+    assert name == "NetBox GET dcim/interfaces/{id}/trace"
+    assert attributes["netbox.endpoint"] == "dcim/interfaces/42/trace"
+
+
+def test_fallback_recorded_on_span(client):
+    """When the fallback endpoint is used, the span should say so."""
+    with (
+        patch("netbox_mcp_server.netbox_client.tracer") as mock_tracer,
+        patch.object(client.session, "get") as mock_get,
+    ):
+        span = mock_tracer.start_as_current_span.return_value.__enter__.return_value
+        mock_get.side_effect = [_response(404), _response(200, {"results": []})]
+
+        client.get("core/object-types", fallback_endpoint="extras/object-types")
+
+    span.set_attribute.assert_any_call("netbox.fallback_endpoint", "extras/object-types")
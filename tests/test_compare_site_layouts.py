@@ -0,0 +1,144 @@
+"""Tests for the cross-site prefix/VLAN layout comparison tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_compare_site_layouts
+from tests.conftest import fake_netbox_get, paged
+
+MGMT = {"slug": "mgmt"}
+USERS = {"slug": "users"}
+
+
+def _fake_get(prefixes, vlans):
+    return fake_netbox_get(
+        {
+            "dcim/sites": lambda id, params: {"id": id, "display": f"site-{id}"},
+            "ipam/prefixes": lambda id, params: paged(prefixes[params["site_id"]]),
+            "ipam/vlans": lambda id, params: paged(vlans[params["available_at_site"]]),
+        }
+    )
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_identical_structure_matches(mock_netbox):
+    prefixes = {
+        1: [{"prefix": "10.1.0.0/24", "role": MGMT}],
+        2: [{"prefix": "10.2.0.0/24", "role": MGMT}],
+    }
+    vlan = {"vid": 10, "name": "mgmt", "role": MGMT}
+    vlans = {1: [vlan], 2: [dict(vlan)]}
+    mock_netbox.get.side_effect = _fake_get(prefixes, vlans)
+
+    result = netbox_compare_site_layouts(site_id=1, reference_site_id=2)
+
+    assert result["matches"] is True
+    assert result["site"] == "site-1"
+    assert result["reference_site"] == "site-2"
+    assert result["vlans"]["collisions"] == {"site": [], "reference_site": []}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_reports_structural_differences(mock_netbox):
+    prefixes = {
+        1: [
+            {"prefix": "10.1.0.0/25", "role": MGMT},
+            {"prefix": "10.1.9.0/24", "role": None},
+        ],
+        2: [
+            {"prefix": "10.2.0.0/24", "role": MGMT},
+            {"prefix": "10.2.1.0/22", "role": USERS},
+            {"prefix": "2001:db8::/64", "role": USERS},
+        ],
+    }
+    vlans = {
+        1: [{"vid": 10, "name": "management", "role": MGMT}, {"vid": 99, "name": "temp"}],
+        2: [{"vid": 10, "name": "mgmt", "role": MGMT}, {"vid": 20, "name": "users"}],
+    }
+    mock_netbox.get.side_effect = _fake_get(prefixes, vlans)
+
+    result = netbox_compare_site_layouts(site_id=1, reference_site_id=2)
+
+    assert result["matches"] is False
+    assert result["prefixes"] == [
+        {"role": None, "missing": [], "extra": ["IPv4 /24"]},
+        {"role": "mgmt", "missing": ["IPv4 /24"], "extra": ["IPv4 /25"]},
+        {"role": "users", "missing": ["IPv4 /22", "IPv6 /64"], "extra": []},
+    ]
+    assert result["vlans"]["missing"] == [{"vid": 20, "name": "users", "group": None}]
+    assert result["vlans"]["extra"] == [{"vid": 99, "name": "temp", "group": None}]
+    assert result["vlans"]["mismatched"] == [
+        {
+            "vid": 10,
+            "name": "management",
+            "role": "mgmt",
+            "reference_name": "mgmt",
+            "reference_role": "mgmt",
+        }
+    ]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_same_vid_in_different_groups_is_not_collapsed(mock_netbox):
+    """VLANs sharing a VID should each be compared and reported as a collision."""
+    prefixes = {1: [], 2: []}
+    vlans = {
+        1: [{"vid": 10, "name": "mgmt", "role": MGMT, "group": {"name": "site-1"}}],
+        2: [
+            {"vid": 10, "name": "mgmt", "role": MGMT, "group": {"name": "site-2"}},
+            {"vid": 10, "name": "oob", "role": MGMT, "group": {"name": "region"}},
+        ],
+    }
+    mock_netbox.get.side_effect = _fake_get(prefixes, vlans)
+
+    result = netbox_compare_site_layouts(site_id=1, reference_site_id=2)
+
+    assert result["matches"] is False
+    assert result["vlans"]["missing"] == [{"vid": 10, "name": "oob", "group": "region"}]
+    assert result["vlans"]["extra"] == []
+    assert result["vlans"]["mismatched"] == []
+    assert result["vlans"]["collisions"] == {
+        "site": [],
+        "reference_site": [
+            {
+                "vid": 10,
+                "vlans": [{"name": "mgmt", "group": "site-2"}, {"name": "oob", "group": "region"}],
+            }
+        ],
+    }
+    vlan_params = mock_netbox.get.call_args_list[-1].kwargs["params"]
+    assert vlan_params["available_at_site"] == 2
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_same_vid_and_name_in_two_groups_both_compared(mock_netbox):
+    """Identical VLANs in two groups at one site must not collapse into one entry."""
+    prefixes = {1: [], 2: []}
+    vlans = {
+        1: [
+            {"vid": 10, "name": "mgmt", "role": MGMT, "group": {"name": "pod-a"}},
+            {"vid": 10, "name": "mgmt", "role": MGMT, "group": {"name": "pod-b"}},
+        ],
+        2: [{"vid": 10, "name": "mgmt", "role": MGMT, "group": {"name": "pod-a"}}],
+    }
+    mock_netbox.get.side_effect = _fake_get(prefixes, vlans)
+
+    result = netbox_compare_site_layouts(site_id=1, reference_site_id=2)
+
+    assert result["matches"] is False
+    assert result["vlans"]["extra"] == [{"vid": 10, "name": "mgmt", "group": "pod-b"}]
+    assert result["vlans"]["missing"] == []
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_per_site_groups_still_match(mock_netbox):
+    """The same VLAN in each site's own group is a match, not missing plus extra."""
+    prefixes = {1: [], 2: []}
+    vlans = {
+        1: [{"vid": 10, "name": "mgmt", "role": MGMT, "group": {"name": "site-1"}}],
+        2: [{"vid": 10, "name": "mgmt", "role": MGMT, "group": {"name": "site-2"}}],
+    }
+    mock_netbox.get.side_effect = _fake_get(prefixes, vlans)
+
+    result = netbox_compare_site_layouts(site_id=1, reference_site_id=2)
+
+    assert result["matches"] is True
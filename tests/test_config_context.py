@@ -0,0 +1,34 @@
+"""Tests for the config context retrieval tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_get_config_context
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_device_config_context_requests_context_fields(mock_netbox):
+    """The device endpoint should be asked for the merged and local context only."""
+    mock_netbox.get.return_value = {
+        "id": 1,
+        "name": "leaf1",
+        "config_context": {"ntp": ["10.0.0.1"]},
+        "local_context_data": None,
+    }
+
+    result = netbox_get_config_context(object_type="dcim.device", object_id=1)
+
+    mock_netbox.get.assert_called_once_with(
+        "dcim/devices/1",
+        params={"fields": "id,name,config_context,local_context_data"},
+    )
+    assert result["config_context"] == {"ntp": ["10.0.0.1"]}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_virtual_machine_uses_vm_endpoint(mock_netbox):
+    """Virtual machines should be read from the virtualization endpoint."""
+    mock_netbox.get.return_value = {"id": 2, "config_context": {}}
+
+    netbox_get_config_context(object_type="virtualization.virtualmachine", object_id=2)
+
+    assert mock_netbox.get.call_args[0][0] == "virtualization/virtual-machines/2"
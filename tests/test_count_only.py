@@ -0,0 +1,48 @@
+"""Tests for netbox_get_objects count_only mode."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_get_objects
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_count_only_requests_one_brief_object(mock_netbox):
+    """count_only should ask NetBox for a single brief record and return just the count."""
+    mock_netbox.get.return_value = {
+        "count": 87,
+        "next": "https://netbox.example.com/api/dcim/devices/?limit=1&offset=1",
+        "previous": None,
+        "results": [{"id": 1, "display": "sw-01"}],
+    }
+
+    result = netbox_get_objects(
+        "dcim.device",
+        {"site_id": 1, "status": "active"},
+        fields=["id", "name", "comments"],
+        limit=50,
+        offset=100,
+        ordering="name",
+        count_only=True,
+    )
+
+    assert result == {"count": 87}
+    params = mock_netbox.get.call_args.kwargs["params"]
+    assert params == {"site_id": 1, "status": "active", "limit": 1, "offset": 0, "brief": "1"}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_count_only_for_several_types(mock_netbox):
+    """For a list of types, the total and per-type counts should be returned."""
+    mock_netbox.get.side_effect = [
+        {"count": 3, "next": None, "previous": None, "results": [{"id": 1}]},
+        {"count": 4, "next": None, "previous": None, "results": [{"id": 2}]},
+    ]
+
+    result = netbox_get_objects(
+        ["dcim.device", "virtualization.virtualmachine"], {"q": "web"}, count_only=True
+    )
+
+    assert result == {
+        "count": 7,
+        "counts": {"dcim.device": 3, "virtualization.virtualmachine": 4},
+    }
@@ -0,0 +1,87 @@
+"""Tests for the custom field schema tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_get_custom_fields
+from tests.conftest import fake_netbox_get, paged
+
+
+FIELDS = [
+    {
+        "name": "owner",
+        "label": "Owner",
+        "type": {"value": "select", "label": "Selection"},
+        "required": True,
+        "default": "neteng",
+        "description": "Owning team",
+        "group_name": "Ops",
+        "filter_logic": {"value": "exact", "label": "Exact"},
+        "related_object_type": None,
+        "validation_minimum": None,
+        "validation_maximum": None,
+        "validation_regex": "",
+        "choice_set": {"id": 3, "display": "Teams"},
+    },
+    {
+        "name": "rack_units_reserved",
+        "label": "",
+        "type": {"value": "integer", "label": "Integer"},
+        "required": False,
+        "default": None,
+        "description": "",
+        "group_name": "",
+        "filter_logic": {"value": "loose", "label": "Loose"},
+        "related_object_type": None,
+        "validation_minimum": 0,
+        "validation_maximum": 42,
+        "validation_regex": "",
+        "choice_set": None,
+    },
+]
+
+
+CHOICE_SETS = [
+    {
+        "id": 3,
+        "base_choices": None,
+        "extra_choices": [["neteng", "Network Eng"], ["dcops", "DC Ops"]],
+    }
+]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_lists_fields_with_types_and_choices(mock_netbox):
+    """Select fields should carry their choice values; others an empty list."""
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"extras/custom-fields": FIELDS, "extras/custom-field-choice-sets": CHOICE_SETS}
+    )
+
+    owner, reserved = netbox_get_custom_fields("dcim.device")
+
+    assert owner["type"] == "select"
+    assert owner["required"] is True
+    assert owner["choices"] == ["neteng", "dcops"]
+    assert owner["filter_logic"] == "exact"
+    assert reserved["type"] == "integer"
+    assert reserved["validation"] == {"minimum": 0, "maximum": 42, "regex": ""}
+    assert reserved["choices"] == []
+    field_params = mock_netbox.get.call_args_list[0].kwargs["params"]
+    assert field_params["object_type"] == "dcim.device"
+    choice_params = mock_netbox.get.call_args_list[1].kwargs["params"]
+    assert choice_params["id"] == [3]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_no_choice_set_query_without_select_fields(mock_netbox):
+    mock_netbox.get.return_value = paged(FIELDS[1:])
+
+    netbox_get_custom_fields("ipam.prefix")
+
+    assert mock_netbox.get.call_count == 1
+
+
+def test_invalid_object_type():
+    with pytest.raises(ValueError):
+        netbox_get_custom_fields("dcim.nonsense")
@@ -0,0 +1,98 @@
+"""Tests for the deployment summary resource."""
+
+import threading
+import time
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server import server
+from netbox_mcp_server.server import deployment_summary
+
+
+@pytest.fixture(autouse=True)
+def reset_summary_cache():
+    """Each test starts without a cached summary."""
+    server._deployment_summary_cache = None
+    yield
+    server._deployment_summary_cache = None
+
+
+def _fake_get(endpoint, params=None, fallback_endpoint=None):
+    params = params or {}
+    if endpoint == "dcim/regions":
+        return {"count": 1, "next": None, "results": [{"id": 1, "display": "Europe"}]}
+    if endpoint == "ipam/prefixes":
+        results = [
+            {"prefix": "10.0.0.0/24", "is_pool": False},
+            {"prefix": "10.0.1.0/25", "is_pool": False},
+        ]
+        return {"count": 2, "next": None, "results": results}
+    if endpoint == "ipam/ip-addresses":
+        return {"count": 190, "next": None, "results": []}
+    if endpoint == "core/object-changes":
+        counts = {"create": 5, "update": 20, "delete": 1}
+        return {"count": counts[params["action"]], "next": None, "results": []}
+    if endpoint == "dcim/devices" and "region_id" in params:
+        return {"count": 40, "next": None, "results": []}
+    return {"count": 3, "next": None, "results": []}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_summary_contents(mock_netbox):
+    """The summary should combine totals, regions, IPv4 utilization and change volume."""
+    mock_netbox.get.side_effect = _fake_get
+
+    summary = deployment_summary()
+
+    assert summary["totals"]["dcim.site"] == 3
+    assert summary["regions"] == [{"region": "Europe", "sites": 3, "devices": 40}]
+    assert summary["ipv4_utilization"] == {"used": 190, "capacity": 380, "percent": 50.0}
+    assert summary["changes_last_7_days"] == {"create": 5, "update": 20, "delete": 1}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_summary_served_from_cache_within_ttl(mock_netbox):
+    """A second read within the TTL should not query NetBox again."""
+    mock_netbox.get.side_effect = _fake_get
+
+    first = deployment_summary()
+    calls = mock_netbox.get.call_count
+    second = deployment_summary()
+
+    assert second is first
+    assert mock_netbox.get.call_count == calls
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_summary_rebuilt_after_ttl(mock_netbox):
+    """Once the TTL has passed, the summary should be rebuilt."""
+    mock_netbox.get.side_effect = _fake_get
+
+    with patch("netbox_mcp_server.server.time.monotonic", return_value=1000.0):
+        deployment_summary()
+    calls = mock_netbox.get.call_count
+    with patch("netbox_mcp_server.server.time.monotonic", return_value=1301.0):
+        deployment_summary()
+
+    assert mock_netbox.get.call_count == 2 * calls
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_concurrent_readers_share_one_rebuild(mock_netbox):
+    """Readers arriving while the summary is being built should not start their own build."""
+    built = []
+
+    def slow_build():
+        built.append(1)
+        time.sleep(0.05)
+        return {"totals": {}}
+
+    with patch("netbox_mcp_server.server._build_deployment_summary", slow_build):
+        readers = [threading.Thread(target=deployment_summary) for _ in range(5)]
+        for reader in readers:
+            reader.start()
+        for reader in readers:
+            reader.join()
+
+    assert len(built) == 1
@@ -0,0 +1,72 @@
+"""Tests for the device lifecycle/EOL report tool."""
+
+import datetime
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_get_device_lifecycle_report
+from tests.conftest import paged
+
+TODAY = datetime.date.today()
+
+
+def _days(offset):
+    return (TODAY + datetime.timedelta(days=offset)).isoformat()
+
+
+def _device(device_id, site, **custom_fields):
+    return {
+        "id": device_id,
+        "display": f"dev{device_id}",
+        "site": {"display": site} if site else None,
+        "custom_fields": custom_fields,
+    }
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_groups_past_and_approaching_devices_by_site(mock_netbox):
+    """Only past or approaching devices should be listed, soonest first, per site."""
+    mock_netbox.get.return_value = paged(
+        [
+            _device(1, "DC1", eol_date=_days(30)),
+            _device(2, "DC1", eol_date=_days(-10)),
+            _device(3, "DC2", eol_date=_days(400)),
+            _device(4, "DC2", eol_date=None),
+            _device(5, None, eol_date=_days(1)),
+        ]
+    )
+
+    result = netbox_get_device_lifecycle_report()
+
+    assert [d["id"] for d in result["sites"]["DC1"]] == [2, 1]
+    assert result["sites"]["DC1"][0]["eol_status"] == "past"
+    assert result["sites"]["DC1"][1]["eol_status"] == "approaching"
+    assert "DC2" not in result["sites"]
+    assert [d["id"] for d in result["sites"]["(no site)"]] == [5]
+    assert result["summary"] == {"past": 1, "approaching": 2, "ok": 0, "unknown": 0}
+    assert result["devices_without_eol_date"] == 1
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_support_field_lists_expiring_contracts(mock_netbox):
+    """A device with a healthy EOL but expiring support should still be listed."""
+    mock_netbox.get.return_value = paged(
+        [_device(1, "DC1", eol_date=_days(1000), support_end=_days(-1))]
+    )
+
+    result = netbox_get_device_lifecycle_report(support_field="support_end", within_days=30)
+
+    (entry,) = result["sites"]["DC1"]
+    assert entry["eol_status"] == "ok"
+    assert entry["support_status"] == "past"
+    assert entry["support_end"] == _days(-1)
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_custom_eol_field_and_site_filter(mock_netbox):
+    """The configured EOL field should be read and site_id passed as a filter."""
+    mock_netbox.get.return_value = paged([_device(1, "DC1", end_of_life=_days(-5))])
+
+    result = netbox_get_device_lifecycle_report(eol_field="end_of_life", site_id=3)
+
+    assert result["summary"]["past"] == 1
+    assert mock_netbox.get.call_args[1]["params"]["site_id"] == 3
@@ -0,0 +1,90 @@
+"""Tests for the device neighbor table tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_get_device_neighbors
+from tests.conftest import paged
+
+
+def _port(name, device):
+    return {"id": 1, "name": name, "display": name, "device": {"id": 2, "display": device}}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_neighbor_rows_follow_connected_endpoints(mock_netbox):
+    """Complete paths report the far-end interface, not the patch panel in between."""
+    mock_netbox.get.return_value = paged(
+        [
+            {
+                "id": 10,
+                "name": "xe-0/0/1",
+                "cable": {"id": 5, "display": "#5"},
+                "link_peers": [_port("front-1", "patch-01")],
+                "link_peers_type": "dcim.frontport",
+                "connected_endpoints": [_port("Ethernet1", "spine-01")],
+                "connected_endpoints_type": "dcim.interface",
+                "connected_endpoints_reachable": True,
+            },
+            {
+                "id": 11,
+                "name": "xe-0/0/2",
+                "cable": {"id": 6, "display": "#6"},
+                "link_peers": [_port("front-2", "patch-01")],
+                "link_peers_type": "dcim.frontport",
+                "connected_endpoints": None,
+                "connected_endpoints_type": None,
+                "connected_endpoints_reachable": None,
+            },
+        ]
+    )
+
+    result = netbox_get_device_neighbors(device_id=1)
+
+    assert result["neighbors"] == [
+        {
+            "local_interface": "xe-0/0/1",
+            "remote_device": "spine-01",
+            "remote_interface": "Ethernet1",
+            "remote_type": "dcim.interface",
+            "cable": "#5",
+            "path_complete": True,
+            "reachable": True,
+        },
+        {
+            "local_interface": "xe-0/0/2",
+            "remote_device": "patch-01",
+            "remote_interface": "front-2",
+            "remote_type": "dcim.frontport",
+            "cable": "#6",
+            "path_complete": False,
+            "reachable": False,
+        },
+    ]
+    assert result["remote_devices"] == ["patch-01", "spine-01"]
+    params = mock_netbox.get.call_args.kwargs["params"]
+    assert params["device_id"] == 1
+    assert params["cabled"] == "true"
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_circuit_endpoint_uses_circuit_as_remote(mock_netbox):
+    mock_netbox.get.return_value = paged(
+        [
+            {
+                "id": 10,
+                "name": "ge-0/0/0",
+                "cable": {"id": 7, "display": "#7"},
+                "link_peers": [],
+                "connected_endpoints": [
+                    {"id": 3, "display": "A", "circuit": {"id": 4, "display": "CID-1001"}}
+                ],
+                "connected_endpoints_type": "circuits.circuittermination",
+                "connected_endpoints_reachable": True,
+            }
+        ]
+    )
+
+    (row,) = netbox_get_device_neighbors(device_id=1)["neighbors"]
+
+    assert row["remote_device"] == "CID-1001"
+    assert row["remote_interface"] == "A"
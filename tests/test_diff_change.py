@@ -0,0 +1,69 @@
+"""Tests for the changelog diff tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_diff_change
+
+
+def _change(action, prechange, postchange):
+    return {
+        "id": 42,
+        "time": "2025-01-01T00:00:00Z",
+        "user_name": "admin",
+        "action": {"value": action, "label": action.title()},
+        "changed_object_type": "dcim.device",
+        "changed_object_id": 7,
+        "object_repr": "sw-01",
+        "prechange_data": prechange,
+        "postchange_data": postchange,
+    }
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_update_returns_only_changed_fields(mock_netbox):
+    """Unchanged fields and last_updated should be left out of an update diff."""
+    mock_netbox.get.return_value = _change(
+        "update",
+        {"name": "sw-01", "status": "planned", "last_updated": "2024-12-01T00:00:00Z"},
+        {"name": "sw-01", "status": "active", "last_updated": "2025-01-01T00:00:00Z"},
+    )
+
+    result = netbox_diff_change(changelog_id=42)
+
+    mock_netbox.get.assert_called_once_with(
+        "core/object-changes/42", fallback_endpoint="extras/object-changes/42"
+    )
+    assert result["action"] == "update"
+    assert result["object_repr"] == "sw-01"
+    assert result["changes"] == [{"field": "status", "before": "planned", "after": "active"}]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_nested_fields_are_compared_by_path(mock_netbox):
+    """Changes inside nested dicts should be reported with dotted field paths."""
+    mock_netbox.get.return_value = _change(
+        "update",
+        {"custom_fields": {"owner": "neteng", "ticket": "CHG-1"}, "tags": ["a"]},
+        {"custom_fields": {"owner": "dcops", "ticket": "CHG-1"}, "tags": ["a", "b"]},
+    )
+
+    result = netbox_diff_change(changelog_id=42)
+
+    assert result["changes"] == [
+        {"field": "custom_fields.owner", "before": "neteng", "after": "dcops"},
+        {"field": "tags", "before": ["a"], "after": ["a", "b"]},
+    ]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_create_and_delete_use_null_sides(mock_netbox):
+    """Creations have no before values and deletions have no after values."""
+    mock_netbox.get.return_value = _change("create", None, {"name": "sw-02"})
+    assert netbox_diff_change(changelog_id=42)["changes"] == [
+        {"field": "name", "before": None, "after": "sw-02"}
+    ]
+
+    mock_netbox.get.return_value = _change("delete", {"name": "sw-02"}, None)
+    assert netbox_diff_change(changelog_id=42)["changes"] == [
+        {"field": "name", "before": "sw-02", "after": None}
+    ]
@@ -0,0 +1,80 @@
+"""Tests for the event rule / webhook pipeline tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_get_event_pipelines
+from tests.conftest import fake_netbox_get
+
+
+WEBHOOK = {
+    "id": 1,
+    "name": "chatops",
+    "payload_url": "https://hooks.example.com/netbox",
+    "http_method": "POST",
+    "http_content_type": "application/json",
+    "ssl_verification": True,
+    "secret": "s3cret",
+    "additional_headers": "X-Api-Key: abc",
+}
+
+
+def _rule(rule_id, action_object_id, **extra):
+    return {
+        "id": rule_id,
+        "name": f"rule-{rule_id}",
+        "enabled": True,
+        "object_types": ["dcim.device"],
+        "event_types": ["object_created"],
+        "conditions": None,
+        "action_type": {"value": "webhook", "label": "Webhook"},
+        "action_object_type": "extras.webhook",
+        "action_object_id": action_object_id,
+        **extra,
+    }
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_rule_is_joined_to_its_webhook_without_secrets(mock_netbox):
+    """Rules should carry a webhook summary that leaves out secret and headers."""
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"extras/event-rules": [_rule(10, 1)], "extras/webhooks": [WEBHOOK]}
+    )
+
+    result = netbox_get_event_pipelines()
+
+    (rule,) = result["rules"]
+    assert rule["event_types"] == ["object_created"]
+    assert rule["problem"] is None
+    assert rule["webhook"]["payload_url"] == "https://hooks.example.com/netbox"
+    assert "secret" not in rule["webhook"]
+    assert "additional_headers" not in rule["webhook"]
+    assert result["unused_webhooks"] == []
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_missing_and_unused_webhooks_are_flagged(mock_netbox):
+    """A rule targeting a deleted webhook and an uncalled webhook should be reported."""
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"extras/event-rules": [_rule(10, 99)], "extras/webhooks": [WEBHOOK]}
+    )
+
+    result = netbox_get_event_pipelines()
+
+    assert result["rules"][0]["webhook"] is None
+    assert result["rules"][0]["problem"] == "Webhook 99 does not exist"
+    assert [webhook["name"] for webhook in result["unused_webhooks"]] == ["chatops"]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_legacy_event_flags_and_enabled_filter(mock_netbox):
+    """Pre-4.1 type_* flags become event_types; enabled_only filters the rule query."""
+    legacy = _rule(10, 1, event_types=None, type_create=True, type_delete=True)
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"extras/event-rules": [legacy], "extras/webhooks": [WEBHOOK]}
+    )
+
+    result = netbox_get_event_pipelines(enabled_only=True)
+
+    assert result["rules"][0]["event_types"] == ["create", "delete"]
+    rule_params = mock_netbox.get.call_args_list[0].kwargs["params"]
+    assert rule_params["enabled"] == "true"
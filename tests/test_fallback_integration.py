@@ -7,6 +7,7 @@ to the NetBox client for types that have version-dependent endpoints.
 from unittest.mock import patch
 
 from netbox_mcp_server.server import (
+    netbox_get_changelogs,
     netbox_get_object_by_id,
     netbox_get_objects,
     netbox_search_objects,
@@ -176,6 +177,29 @@ def test_search_objects_mixed_types_with_and_without_fallback(mock_netbox):
             assert fallback == "extras/object-types", "core.objecttype should have fallback"
 
 
+# ============================================================================
+# netbox_get_changelogs Fallback Tests
+# ============================================================================
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_get_changelogs_passes_fallback(mock_netbox):
+    """netbox_get_changelogs should fall back to extras/object-changes for NetBox < 4.1."""
+    mock_netbox.get.return_value = {
+        "count": 0,
+        "results": [],
+        "next": None,
+        "previous": None,
+    }
+
+    netbox_get_changelogs(filters={"action": "delete"})
+
+    call_args = mock_netbox.get.call_args
+    assert call_args[0][0] == "core/object-changes"
+    assert call_args[1]["params"] == {"action": "delete"}
+    assert call_args[1]["fallback_endpoint"] == "extras/object-changes"
+
+
 # ============================================================================
 # New Object Types Tests (NetBox 4.4/4.5 additions)
 # ============================================================================
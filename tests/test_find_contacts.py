@@ -0,0 +1,86 @@
+"""Tests for the contact resolution tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_find_contacts_for_object
+from tests.conftest import fake_netbox_get, paged
+
+
+def _assignment(contact_id, name, role, priority):
+    return {
+        "contact": {"id": contact_id, "display": name, "name": name},
+        "role": {"id": 1, "display": role},
+        "priority": {"value": priority, "label": priority.title()} if priority else None,
+    }
+
+
+CONTACTS = [
+    {"id": 1, "name": "Acme NOC", "title": "", "email": "noc@acme.example", "phone": "+1 555"},
+    {"id": 2, "name": "Jo Smith", "title": "Circuit owner", "email": "jo@example.com", "phone": ""},
+]
+
+
+def _fake_get(assignments_by_object):
+    def assignments(id, params):
+        return paged(assignments_by_object.get((params["object_type"], params["object_id"]), []))
+
+    def contacts(id, params):
+        return paged([contact for contact in CONTACTS if contact["id"] in params["id"]])
+
+    return fake_netbox_get(
+        {
+            "circuits/circuits": {"id": 4, "provider": {"id": 7, "display": "Acme"}},
+            "tenancy/contact-assignments": assignments,
+            "tenancy/contacts": contacts,
+        }
+    )
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_circuit_contacts_include_provider_contacts(mock_netbox):
+    """Circuit and provider contacts should be merged, primary first, with emails."""
+    mock_netbox.get.side_effect = _fake_get(
+        {
+            ("circuits.circuit", 4): [_assignment(2, "Jo Smith", "Owner", "secondary")],
+            ("circuits.provider", 7): [_assignment(1, "Acme NOC", "NOC", "primary")],
+        }
+    )
+
+    result = netbox_find_contacts_for_object("circuits.circuit", 4)
+
+    assert result == [
+        {
+            "name": "Acme NOC",
+            "title": "",
+            "email": "noc@acme.example",
+            "phone": "+1 555",
+            "role": "NOC",
+            "priority": "primary",
+            "via": "circuits.provider",
+        },
+        {
+            "name": "Jo Smith",
+            "title": "Circuit owner",
+            "email": "jo@example.com",
+            "phone": "",
+            "role": "Owner",
+            "priority": "secondary",
+            "via": "circuits.circuit",
+        },
+    ]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_without_related_only_object_contacts(mock_netbox):
+    mock_netbox.get.side_effect = _fake_get({})
+
+    assert netbox_find_contacts_for_object("circuits.circuit", 4, include_related=False) == []
+    endpoints = [c.args[0] for c in mock_netbox.get.call_args_list]
+    assert endpoints == ["tenancy/contact-assignments"]
+
+
+def test_invalid_object_type():
+    with pytest.raises(ValueError):
+        netbox_find_contacts_for_object("dcim.nonsense", 1)
@@ -0,0 +1,79 @@
+"""Tests for the site-wide free port finder."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_find_free_ports
+from tests.conftest import fake_netbox_get, paged
+
+
+def _interface(name, device_id, **kwargs):
+    interface = {
+        "name": name,
+        "type": {"value": "1000base-t"},
+        "enabled": True,
+        "cable": None,
+        "mark_connected": False,
+        "device": {"id": device_id, "display": f"sw{device_id}"},
+    }
+    interface.update(kwargs)
+    return interface
+
+
+INTERFACES = [
+    _interface("ge-1", 1),
+    _interface("ge-2", 1),
+    _interface("ge-3", 1, cable={"id": 5}),
+    _interface("ge-1", 2),
+    _interface("ge-1", 3, mark_connected=True),
+]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_ranks_devices_by_free_ports(mock_netbox):
+    """Devices should be listed with their free ports, most free first."""
+    mock_netbox.get.return_value = paged(INTERFACES)
+
+    result = netbox_find_free_ports(site_id=1, interface_type="1000base-t")
+
+    assert result == [
+        {"device_id": 1, "device": "sw1", "free_interfaces": ["ge-1", "ge-2"], "free_count": 2},
+        {"device_id": 2, "device": "sw2", "free_interfaces": ["ge-1"], "free_count": 1},
+    ]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_min_free_excludes_devices_below_threshold(mock_netbox):
+    """Devices with fewer than min_free free ports should be dropped."""
+    mock_netbox.get.return_value = paged(INTERFACES)
+
+    result = netbox_find_free_ports(site_id=1, interface_type="1000base-t", min_free=2)
+
+    assert [candidate["device_id"] for candidate in result] == [1]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_role_id_restricts_candidates(mock_netbox):
+    """With role_id, only devices holding that role should be considered."""
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"dcim/devices": [{"id": 2}], "dcim/interfaces": INTERFACES}
+    )
+
+    result = netbox_find_free_ports(site_id=1, interface_type="1000base-t", role_id=7)
+
+    assert [candidate["device_id"] for candidate in result] == [2]
+    device_call = mock_netbox.get.call_args_list[-1]
+    assert device_call.args[0] == "dcim/devices"
+    assert device_call.kwargs["params"]["role_id"] == 7
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_interface_query_filters(mock_netbox):
+    """The interface query should be scoped by site and type and skip cabled ports."""
+    mock_netbox.get.return_value = paged([])
+
+    netbox_find_free_ports(site_id=4, interface_type="10gbase-x-sfpp")
+
+    params = mock_netbox.get.call_args[1]["params"]
+    assert params["site_id"] == 4
+    assert params["type"] == "10gbase-x-sfpp"
+    assert params["cabled"] is False
@@ -0,0 +1,49 @@
+"""Tests for the HTTP health and readiness probe routes."""
+
+import asyncio
+from unittest.mock import MagicMock, patch
+
+import httpx
+
+from netbox_mcp_server.server import healthz, readyz
+
+
+def test_healthz_always_ok():
+    """The liveness probe should not depend on NetBox."""
+    response = asyncio.run(healthz(MagicMock()))
+
+    assert response.status_code == 200
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_readyz_ready_when_netbox_answers(mock_netbox):
+    """The readiness probe should pass when the NetBox status endpoint responds."""
+    mock_netbox.get.return_value = {"netbox-version": "4.4.0"}
+
+    response = asyncio.run(readyz(MagicMock()))
+
+    assert response.status_code == 200
+    mock_netbox.get.assert_called_once_with("status", use_cache=False)
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_readyz_unavailable_on_rejected_token(mock_netbox):
+    """A rejected token should fail readiness with the auth category only."""
+    mock_netbox.get.side_effect = httpx.HTTPStatusError(
+        "Forbidden", request=MagicMock(), response=MagicMock(status_code=403)
+    )
+
+    response = asyncio.run(readyz(MagicMock()))
+
+    assert response.status_code == 503
+    assert b'"reason":"auth"' in response.body
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_readyz_unavailable_when_netbox_unreachable(mock_netbox):
+    """Connection failures should fail readiness."""
+    mock_netbox.get.side_effect = httpx.ConnectError("Connection refused")
+
+    response = asyncio.run(readyz(MagicMock()))
+
+    assert response.status_code == 503
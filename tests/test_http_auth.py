@@ -11,7 +11,8 @@ from fastmcp import FastMCP
 from pydantic import SecretStr
 from starlette.testclient import TestClient
 
-from netbox_mcp_server.server import build_http_auth
+from netbox_mcp_server.config import Settings
+from netbox_mcp_server.server import build_http_auth, build_oauth_auth
 
 TOKEN = "test-secret-token"
 
@@ -87,3 +88,38 @@ def test_non_ascii_bearer_is_rejected_not_errored() -> None:
     assert asyncio.run(verifier.verify_token("caf\xe9")) is None
     # Correct token still authenticates.
     assert asyncio.run(verifier.verify_token(TOKEN)) is not None
+
+
+def _oauth_settings(**overrides) -> Settings:
+    values = {
+        "netbox_url": "https://netbox.example.com/",
+        "netbox_token": "tok",
+        "transport": "http",
+        "mcp_oauth_issuer": "https://sso.example.com/",
+        "mcp_oauth_jwks_uri": "https://sso.example.com/.well-known/jwks.json",
+        "mcp_base_url": "https://mcp.example.com",
+    }
+    return Settings(**{**values, **overrides}, _env_file=None)
+
+
+def test_oauth_disabled_without_issuer() -> None:
+    settings = _oauth_settings(mcp_oauth_issuer=None, mcp_oauth_jwks_uri=None, mcp_base_url=None)
+    assert build_oauth_auth(settings) is None
+
+
+def test_oauth_request_without_token_is_rejected() -> None:
+    mcp: FastMCP = FastMCP(name="test-netbox-mcp")
+    mcp.auth = build_oauth_auth(_oauth_settings(mcp_oauth_audience="netbox-mcp"))
+    with TestClient(mcp.http_app()) as client:
+        response = client.post("/mcp/", json=_INITIALIZE, headers=_HEADERS)
+    assert response.status_code == 401
+
+
+def test_oauth_serves_protected_resource_metadata() -> None:
+    mcp: FastMCP = FastMCP(name="test-netbox-mcp")
+    mcp.auth = build_oauth_auth(_oauth_settings(mcp_oauth_required_scopes=["netbox:read"]))
+    with TestClient(mcp.http_app()) as client:
+        response = client.get("/.well-known/oauth-protected-resource/mcp")
+    assert response.status_code == 200
+    metadata = response.json()
+    assert metadata["authorization_servers"] == ["https://sso.example.com/"]
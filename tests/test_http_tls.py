@@ -0,0 +1,40 @@
+"""Tests for TLS and mutual TLS options on the HTTP transport."""
+
+import ssl
+
+from netbox_mcp_server.config import Settings
+from netbox_mcp_server.server import build_tls_config
+
+
+def _settings(**overrides) -> Settings:
+    return Settings(
+        netbox_url="https://netbox.example.com/",
+        netbox_token="tok",
+        transport="http",
+        _env_file=None,
+        **overrides,
+    )
+
+
+def test_no_tls_by_default():
+    assert build_tls_config(_settings()) == {}
+
+
+def test_server_certificate_enables_https_without_client_certs():
+    config = build_tls_config(_settings(tls_certfile="server.pem", tls_keyfile="server.key"))
+
+    assert config == {"ssl_certfile": "server.pem", "ssl_keyfile": "server.key"}
+
+
+def test_client_ca_requires_client_certificates():
+    config = build_tls_config(
+        _settings(
+            tls_certfile="server.pem",
+            tls_keyfile="server.key",
+            tls_client_ca_file="clients-ca.pem",
+        )
+    )
+
+    assert config["ssl_ca_certs"] == "clients-ca.pem"
+    assert config["ssl_cert_reqs"] == ssl.CERT_REQUIRED
+
@@ -0,0 +1,78 @@
+"""Tests for the device interface summary tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_get_device_interface_summary
+
+
+def _interface(name, type_value, **kwargs):
+    interface = {
+        "name": name,
+        "type": {"value": type_value, "label": type_value},
+        "speed": None,
+        "duplex": None,
+        "lag": None,
+        "enabled": True,
+        "cable": None,
+        "mark_connected": False,
+    }
+    interface.update(kwargs)
+    return interface
+
+
+INTERFACES = [
+    _interface("xe-0/0/0", "10gbase-x-sfpp", speed=10000000, lag={"id": 9, "name": "ae0"}),
+    _interface("xe-0/0/1", "10gbase-x-sfpp", speed=10000000, cable={"id": 1}),
+    _interface("xe-0/0/2", "10gbase-x-sfpp", speed=10000000, enabled=False),
+    _interface("xe-0/0/3", "10gbase-x-sfpp", speed=10000000, mark_connected=True),
+    _interface("ge-0/0/0", "1000base-t", duplex={"value": "full", "label": "Full"}),
+    _interface("ae0", "lag"),
+]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_groups_interfaces_by_type_with_free_counts(mock_netbox):
+    """Only enabled, uncabled, unmarked physical ports should count as free."""
+    mock_netbox.get.return_value = {"count": 6, "next": None, "results": INTERFACES}
+
+    result = netbox_get_device_interface_summary(device_id=1)
+
+    assert result["total"] == 6
+    assert result["by_type"] == {
+        "10gbase-x-sfpp": {"total": 4, "free": 1},
+        "1000base-t": {"total": 1, "free": 1},
+        "lag": {"total": 1, "free": 0},
+    }
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_reports_utilization_and_free_port_names(mock_netbox):
+    """Each interface should count once as disabled, connected or unconnected."""
+    mock_netbox.get.return_value = {"count": 6, "next": None, "results": INTERFACES}
+
+    result = netbox_get_device_interface_summary(device_id=1)
+
+    assert result["utilization"] == {"connected": 2, "unconnected": 3, "disabled": 1}
+    assert result["free_ports"] == {"10gbase-x-sfpp": ["xe-0/0/0"], "1000base-t": ["ge-0/0/0"]}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_reports_speed_duplex_and_lag_members(mock_netbox):
+    """Speed and duplex should be counted with 'unset' fallbacks; LAG members listed."""
+    mock_netbox.get.return_value = {"count": 6, "next": None, "results": INTERFACES}
+
+    result = netbox_get_device_interface_summary(device_id=1)
+
+    assert result["by_speed"] == {"10000000": 4, "unset": 2}
+    assert result["by_duplex"] == {"unset": 5, "full": 1}
+    assert result["lags"] == {"ae0": ["xe-0/0/0"]}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_filters_interfaces_by_device(mock_netbox):
+    """Interfaces should be requested for the given device only."""
+    mock_netbox.get.return_value = {"count": 0, "next": None, "results": []}
+
+    netbox_get_device_interface_summary(device_id=42)
+
+    assert mock_netbox.get.call_args[1]["params"]["device_id"] == 42
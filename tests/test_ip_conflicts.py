@@ -0,0 +1,90 @@
+"""Tests for the IP conflict detection tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_find_ip_conflicts
+from tests.conftest import fake_netbox_get
+
+
+def _ip(ip_id, address, vrf=None, role=None, interface=None):
+    return {
+        "id": ip_id,
+        "address": address,
+        "vrf": vrf,
+        "status": {"value": "active", "label": "Active"},
+        "role": {"value": role, "label": role} if role else None,
+        "assigned_object": {"id": 1, "display": interface} if interface else None,
+    }
+
+
+def _prefix(prefix_id, prefix, vrf=None):
+    return {
+        "id": prefix_id,
+        "prefix": prefix,
+        "vrf": vrf,
+        "status": {"value": "active", "label": "Active"},
+        "scope": None,
+    }
+
+
+VRF_RED = {"id": 5, "display": "RED"}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_same_host_in_same_vrf_conflicts_regardless_of_mask(mock_netbox):
+    """Same host address in one VRF conflicts; other VRFs and shared roles do not."""
+    ips = [
+        _ip(1, "10.0.0.1/24", interface="eth0"),
+        _ip(2, "10.0.0.1/32", interface="lo0"),
+        _ip(3, "10.0.0.1/24", vrf=VRF_RED),
+        _ip(4, "10.0.0.254/24", role="vrrp"),
+        _ip(5, "10.0.0.254/24", role="vrrp"),
+    ]
+    mock_netbox.get.side_effect = fake_netbox_get({"ipam/ip-addresses": ips, "ipam/prefixes": []})
+
+    result = netbox_find_ip_conflicts()
+
+    assert result["summary"] == {"duplicate_ips": 1, "duplicate_prefixes": 0}
+    (conflict,) = result["duplicate_ips"]
+    assert conflict["address"] == "10.0.0.1"
+    assert conflict["vrf"] is None
+    assert [obj["assigned_object"] for obj in conflict["objects"]] == ["eth0", "lo0"]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_duplicate_prefixes_in_same_vrf(mock_netbox):
+    """The same network twice in a VRF conflicts; nested prefixes do not."""
+    prefixes = [
+        _prefix(1, "10.1.0.0/24", vrf=VRF_RED),
+        _prefix(2, "10.1.0.0/24", vrf=VRF_RED),
+        _prefix(3, "10.1.0.0/24"),
+        _prefix(4, "10.1.0.0/25", vrf=VRF_RED),
+    ]
+    mock_netbox.get.side_effect = fake_netbox_get(
+        {"ipam/ip-addresses": [], "ipam/prefixes": prefixes}
+    )
+
+    result = netbox_find_ip_conflicts()
+
+    assert result["duplicate_prefixes"] == [
+        {
+            "prefix": "10.1.0.0/24",
+            "vrf": "RED",
+            "objects": [
+                {"id": 1, "status": "active", "scope": None},
+                {"id": 2, "status": "active", "scope": None},
+            ],
+        }
+    ]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_scope_filters_are_passed_through(mock_netbox):
+    mock_netbox.get.side_effect = fake_netbox_get({"ipam/ip-addresses": [], "ipam/prefixes": []})
+
+    netbox_find_ip_conflicts(parent="10.0.0.0/16", vrf_id=5)
+
+    ip_params = mock_netbox.get.call_args_list[0].kwargs["params"]
+    prefix_params = mock_netbox.get.call_args_list[1].kwargs["params"]
+    assert (ip_params["parent"], ip_params["vrf_id"]) == ("10.0.0.0/16", 5)
+    assert (prefix_params["within_include"], prefix_params["vrf_id"]) == ("10.0.0.0/16", 5)
@@ -0,0 +1,62 @@
+"""Tests for the module type profile attribute query tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_find_module_types_by_attributes
+from tests.conftest import fake_netbox_get
+
+PROFILE = {
+    "id": 3,
+    "name": "Power supply",
+    "description": "",
+    "schema": {
+        "properties": {"wattage": {"type": "integer"}, "input_current": {"type": "string"}},
+        "required": ["wattage"],
+    },
+}
+
+
+def _fake_get(module_types):
+    return fake_netbox_get(
+        {"dcim/module-type-profiles": PROFILE, "dcim/module-types": module_types}
+    )
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_filters_module_types_by_attribute_values(mock_netbox):
+    """Attribute values should become attr_ filters scoped to the profile."""
+    mock_netbox.get.side_effect = _fake_get(
+        [
+            {
+                "id": 10,
+                "display": "PWR-750",
+                "manufacturer": {"display": "Acme"},
+                "model": "PWR-750",
+                "part_number": "P750",
+                "attributes": {"wattage": 750},
+            }
+        ]
+    )
+
+    result = netbox_find_module_types_by_attributes(profile_id=3, attributes={"wattage": 750})
+
+    params = mock_netbox.get.call_args[1]["params"]
+    assert params["profile_id"] == 3
+    assert params["attr_wattage"] == 750
+    assert result["profile"]["attributes"] == PROFILE["schema"]["properties"]
+    assert result["profile"]["required"] == ["wattage"]
+    assert result["module_types"][0]["manufacturer"] == "Acme"
+    assert result["module_types"][0]["attributes"] == {"wattage": 750}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_unknown_attribute_rejected_with_valid_names(mock_netbox):
+    """Attributes not in the profile schema should be rejected before querying module types."""
+    mock_netbox.get.side_effect = _fake_get([])
+
+    with pytest.raises(ValueError, match="Valid attributes: input_current, wattage"):
+        netbox_find_module_types_by_attributes(profile_id=3, attributes={"watts": 750})
+
+    assert mock_netbox.get.call_count == 1
@@ -0,0 +1,49 @@
+"""Tests for querying several object types in one netbox_get_objects call."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_get_objects
+
+
+def _fake_get(endpoint, params=None, fallback_endpoint=None):
+    pages = {
+        "dcim/devices": {"count": 3, "results": [{"id": 1, "name": "web-01"}]},
+        "virtualization/virtual-machines": {"count": 1, "results": [{"id": 9, "name": "web-vm"}]},
+    }
+    return pages[endpoint]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_results_merged_in_given_order_with_labels(mock_netbox):
+    mock_netbox.get.side_effect = _fake_get
+
+    result = netbox_get_objects(
+        ["virtualization.virtualmachine", "dcim.device"],
+        {"name__isw": "web-"},
+        fields=["id", "name"],
+    )
+
+    assert result["count"] == 4
+    assert result["counts"] == {"virtualization.virtualmachine": 1, "dcim.device": 3}
+    assert [(o["_object_type"], o["id"]) for o in result["results"]] == [
+        ("virtualization.virtualmachine", 9),
+        ("dcim.device", 1),
+    ]
+    for call in mock_netbox.get.call_args_list:
+        assert call[1]["params"]["name__isw"] == "web-"
+        assert call[1]["params"]["fields"] == "id,name"
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_invalid_type_rejected_before_any_query(mock_netbox):
+    with pytest.raises(ValueError, match="dcim.nothing"):
+        netbox_get_objects(["dcim.device", "dcim.nothing"], {})
+
+    mock_netbox.get.assert_not_called()
+
+
+def test_empty_type_list_rejected():
+    with pytest.raises(ValueError, match="at least one type"):
+        netbox_get_objects([], {})
@@ -0,0 +1,66 @@
+"""Tests for optional unit and timezone normalization of tool responses."""
+
+from unittest.mock import patch
+from zoneinfo import ZoneInfo
+
+from netbox_mcp_server.server import (
+    netbox_get_changelogs,
+    netbox_get_object_by_id,
+    netbox_get_objects,
+)
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_responses_untouched_by_default(mock_netbox):
+    """With no options set, NetBox data is returned exactly as received."""
+    interface = {"id": 1, "speed": 10000000, "last_updated": "2025-01-01T12:00:00Z"}
+    mock_netbox.get.return_value = dict(interface)
+
+    result = netbox_get_object_by_id("dcim.interface", 1)
+
+    assert result == interface
+
+
+@patch("netbox_mcp_server.server.normalize_units", True)
+@patch("netbox_mcp_server.server.netbox")
+def test_units_added_next_to_raw_fields(mock_netbox):
+    """Speeds gain a Gbps field and power draws a kW field; raw values are kept."""
+    mock_netbox.get.return_value = {
+        "count": 2,
+        "results": [
+            {"id": 1, "speed": 25000000},
+            {"id": 2, "maximum_draw": 1500, "allocated_draw": None, "speed": True},
+        ],
+    }
+
+    result = netbox_get_objects("dcim.interface", {})
+
+    first, second = result["results"]
+    assert first["speed"] == 25000000
+    assert first["speed_gbps"] == 25
+    assert second["maximum_draw_kw"] == 1.5
+    assert "allocated_draw_kw" not in second
+    assert "speed_gbps" not in second
+
+
+@patch("netbox_mcp_server.server.display_timezone", ZoneInfo("Europe/Berlin"))
+@patch("netbox_mcp_server.server.netbox")
+def test_timestamps_converted_including_nested_data(mock_netbox):
+    """Aware timestamps, nested ones too, move to the display timezone; dates stay."""
+    mock_netbox.get.return_value = {
+        "count": 1,
+        "results": [
+            {
+                "time": "2025-07-01T10:00:00.123456Z",
+                "postchange_data": {"last_updated": "2025-01-15T10:00:00+00:00"},
+                "object_data": {"created": "2025-01-15"},
+            }
+        ],
+    }
+
+    result = netbox_get_changelogs({})
+
+    entry = result["results"][0]
+    assert entry["time"] == "2025-07-01T12:00:00.123456+02:00"
+    assert entry["postchange_data"]["last_updated"] == "2025-01-15T11:00:00+01:00"
+    assert entry["object_data"]["created"] == "2025-01-15"
@@ -0,0 +1,97 @@
+"""Tests for the power chain trace tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_trace_power_chain
+from tests.conftest import fake_netbox_get, paged
+
+
+def _power_port(name, device, endpoint_type=None, endpoints=None):
+    return {
+        "id": 1,
+        "name": name,
+        "device": {"id": device[0], "display": device[1]},
+        "connected_endpoints": endpoints,
+        "connected_endpoints_type": endpoint_type,
+    }
+
+
+SERVER = (1, "srv-01")
+PDU = (2, "pdu-a")
+FEED = {
+    "id": 7,
+    "name": "Feed A1",
+    "power_panel": {"id": 3, "display": "Panel 1"},
+    "status": {"value": "active", "label": "Active"},
+    "voltage": 230,
+    "amperage": 32,
+    "phase": {"value": "single-phase", "label": "Single phase"},
+}
+
+
+def _fake_get(ports_by_device):
+    def power_ports(id, params):
+        key = params.get("device_id") or ("rack", params.get("rack_id"))
+        return paged(ports_by_device.get(key, []))
+
+    return fake_netbox_get({"dcim/power-feeds": FEED, "dcim/power-ports": power_ports})
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_traces_through_pdu_to_feed_and_panel(mock_netbox):
+    """A server PSU on a PDU outlet should resolve to the PDU's feed and panel."""
+    mock_netbox.get.side_effect = _fake_get(
+        {
+            1: [
+                _power_port(
+                    "PSU1",
+                    SERVER,
+                    "dcim.poweroutlet",
+                    [{"id": 5, "name": "Outlet 5", "device": {"id": 2, "display": "pdu-a"}}],
+                ),
+                _power_port("PSU2", SERVER),
+            ],
+            2: [_power_port("Inlet", PDU, "dcim.powerfeed", [{"id": 7, "name": "Feed A1"}])],
+        }
+    )
+
+    result = netbox_trace_power_chain(device_id=1)
+
+    psu1, psu2 = result
+    assert psu1["device"] == "srv-01"
+    assert psu1["paths"] == [
+        [
+            {"type": "dcim.poweroutlet", "name": "Outlet 5", "device": "pdu-a"},
+            {
+                "type": "dcim.powerfeed",
+                "name": "Feed A1",
+                "power_panel": "Panel 1",
+                "status": "active",
+                "voltage": 230,
+                "amperage": 32,
+                "phase": "single-phase",
+            },
+        ]
+    ]
+    assert psu2["paths"] == []
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_rack_trace_fetches_each_feed_once(mock_netbox):
+    feed_port = _power_port("Inlet", PDU, "dcim.powerfeed", [{"id": 7, "name": "Feed A1"}])
+    mock_netbox.get.side_effect = _fake_get({("rack", 9): [feed_port, feed_port]})
+
+    result = netbox_trace_power_chain(rack_id=9)
+
+    assert [port["paths"][0][0]["power_panel"] for port in result] == ["Panel 1", "Panel 1"]
+    feed_calls = [c for c in mock_netbox.get.call_args_list if c.args[0] == "dcim/power-feeds"]
+    assert len(feed_calls) == 1
+
+
+def test_requires_exactly_one_scope():
+    with pytest.raises(ValueError):
+        netbox_trace_power_chain()
+    with pytest.raises(ValueError):
+        netbox_trace_power_chain(device_id=1, rack_id=2)
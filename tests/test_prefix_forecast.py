@@ -0,0 +1,107 @@
+"""Tests for the prefix capacity forecast tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_forecast_prefix_capacity
+from tests.conftest import fake_netbox_get
+
+
+def _change(action, address, vrf=None):
+    data = {"address": address, "vrf": vrf}
+    return {
+        "action": {"value": action, "label": action.title()},
+        "prechange_data": data if action == "delete" else None,
+        "postchange_data": data if action == "create" else None,
+    }
+
+
+def _fake_get(prefixes, changes, used):
+    def ip_count(id, params):
+        return {"count": used[params["parent"]], "next": None, "results": []}
+
+    return fake_netbox_get(
+        {"ipam/prefixes": prefixes, "core/object-changes": changes, "ipam/ip-addresses": ip_count}
+    )
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_projects_exhaustion_from_net_growth(mock_netbox):
+    """Net creates minus deletes over the window should drive the projection."""
+    prefixes = [
+        {"id": 1, "prefix": "10.0.0.0/24", "vrf": None, "is_pool": False},
+        {"id": 2, "prefix": "10.0.1.0/24", "vrf": None, "is_pool": False},
+    ]
+    changes = [
+        *[_change("create", f"10.0.0.{i}/24") for i in range(1, 14)],
+        _change("delete", "10.0.0.200/24"),
+        _change("create", "192.168.0.1/24"),
+        _change("create", "10.0.0.50/24", vrf=9),
+    ]
+    mock_netbox.get.side_effect = _fake_get(
+        prefixes, changes, {"10.0.0.0/24": 194, "10.0.1.0/24": 10}
+    )
+
+    result = netbox_forecast_prefix_capacity(role_id=3, months=6)
+
+    growing, flat = result
+    assert growing["id"] == 1
+    assert growing["size"] == 254
+    assert growing["net_growth"] == 12
+    assert growing["growth_per_month"] == 2.0
+    assert growing["months_to_exhaustion"] == 30.0
+    assert growing["projected_exhaustion_date"] is not None
+    assert flat["id"] == 2
+    assert flat["months_to_exhaustion"] is None
+    assert flat["projected_exhaustion_date"] is None
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_nested_prefixes_share_growth(mock_netbox):
+    """A change counts toward every prefix that contains it, whatever its length."""
+    prefixes = [
+        {"id": 1, "prefix": "10.0.0.0/16", "vrf": None, "is_pool": False},
+        {"id": 2, "prefix": "10.0.1.0/24", "vrf": None, "is_pool": False},
+        {"id": 3, "prefix": "2001:db8::/64", "vrf": None, "is_pool": False},
+    ]
+    changes = [
+        _change("create", "10.0.1.5/24"),
+        _change("create", "10.0.2.5/24"),
+        _change("create", "2001:db8::5/64"),
+    ]
+    used = {"10.0.0.0/16": 0, "10.0.1.0/24": 0, "2001:db8::/64": 0}
+    mock_netbox.get.side_effect = _fake_get(prefixes, changes, used)
+
+    result = netbox_forecast_prefix_capacity(role_id=3)
+
+    growth = {forecast["id"]: forecast["net_growth"] for forecast in result}
+    assert growth == {1: 2, 2: 1, 3: 1}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_queries_are_scoped(mock_netbox):
+    """Prefixes should be filtered by role/site and changes to IP creates/deletes."""
+    mock_netbox.get.side_effect = _fake_get([], [], {})
+
+    netbox_forecast_prefix_capacity(role_id=3, site_id=4)
+
+    prefix_params = mock_netbox.get.call_args_list[0][1]["params"]
+    change_call = mock_netbox.get.call_args_list[1]
+    assert prefix_params["role_id"] == 3
+    assert prefix_params["site_id"] == 4
+    assert change_call[1]["params"]["changed_object_type"] == "ipam.ipaddress"
+    assert change_call[1]["params"]["action"] == ["create", "delete"]
+    assert "time_after" in change_call[1]["params"]
+    assert change_call[1]["params"]["fields"] == "action,prechange_data,postchange_data"
+    assert change_call[1]["fallback_endpoint"] == "extras/object-changes"
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_pool_prefix_counts_every_address(mock_netbox):
+    """Pools have no reserved network/broadcast addresses."""
+    prefixes = [{"id": 1, "prefix": "10.0.0.0/30", "vrf": None, "is_pool": True}]
+    mock_netbox.get.side_effect = _fake_get(prefixes, [], {"10.0.0.0/30": 1})
+
+    (forecast,) = netbox_forecast_prefix_capacity(role_id=3)
+
+    assert forecast["size"] == 4
+    assert forecast["utilization"] == 25.0
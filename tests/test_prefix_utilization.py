@@ -0,0 +1,103 @@
+"""Tests for the prefix utilization tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_get_prefix_utilization
+from tests.conftest import fake_netbox_get, paged
+
+
+def _prefix(prefix_id, prefix, status="active", **extra):
+    return {
+        "id": prefix_id,
+        "prefix": prefix,
+        "vrf": None,
+        "status": {"value": status, "label": status.title()},
+        "is_pool": False,
+        "mark_utilized": False,
+        **extra,
+    }
+
+
+def _fake_get(prefixes, ip_counts, children):
+    def prefix_list(id, params):
+        if "within" in params and "fields" not in params:
+            return {"count": len(children.get(params["within"], [])), "results": []}
+        if params.get("fields") == "prefix":
+            return paged([{"prefix": p} for p in children.get(params["within"], [])])
+        return paged(prefixes)
+
+    def ip_count(id, params):
+        return {"count": ip_counts.get(params["parent"], 0), "results": []}
+
+    return fake_netbox_get({"ipam/prefixes": prefix_list, "ipam/ip-addresses": ip_count})
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_reports_ip_utilization_highest_first(mock_netbox):
+    """Non-container prefixes should count IPs against usable addresses."""
+    prefixes = [_prefix(1, "10.0.0.0/24"), _prefix(2, "10.0.1.0/24")]
+    mock_netbox.get.side_effect = _fake_get(
+        prefixes, {"10.0.0.0/24": 127, "10.0.1.0/24": 254}, {"10.0.1.0/24": ["10.0.1.0/28"]}
+    )
+
+    result = netbox_get_prefix_utilization(within="10.0.0.0/16", prefix_length=24)
+
+    assert [entry["prefix"] for entry in result] == ["10.0.1.0/24", "10.0.0.0/24"]
+    assert result[0]["utilization"] == 100.0
+    assert result[0]["child_prefixes"] == 1
+    assert result[1] == {
+        "id": 1,
+        "prefix": "10.0.0.0/24",
+        "vrf": None,
+        "status": "active",
+        "size": 254,
+        "child_prefixes": 0,
+        "ip_addresses": 127,
+        "utilization": 50.0,
+    }
+    list_params = mock_netbox.get.call_args_list[0].kwargs["params"]
+    assert list_params["within"] == "10.0.0.0/16"
+    assert list_params["mask_length"] == 24
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_container_counts_child_prefix_space(mock_netbox):
+    """Containers should use collapsed child prefix space, not IP counts."""
+    prefixes = [_prefix(1, "10.0.0.0/22", status="container")]
+    children = {"10.0.0.0/22": ["10.0.0.0/24", "10.0.0.0/25", "10.0.1.0/24"]}
+    mock_netbox.get.side_effect = _fake_get(prefixes, {"10.0.0.0/22": 3}, children)
+
+    (entry,) = netbox_get_prefix_utilization(prefix_ids=[1])
+
+    assert entry["size"] == 1024
+    assert entry["child_prefixes"] == 3
+    assert entry["ip_addresses"] == 3
+    assert entry["utilization"] == 50.0
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_min_utilization_and_mark_utilized(mock_netbox):
+    prefixes = [_prefix(1, "10.0.0.0/24", mark_utilized=True), _prefix(2, "10.0.1.0/24")]
+    mock_netbox.get.side_effect = _fake_get(prefixes, {}, {})
+
+    result = netbox_get_prefix_utilization(prefix_ids=[1, 2], min_utilization=80)
+
+    assert [(entry["id"], entry["utilization"]) for entry in result] == [(1, 100.0)]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_limit_applies_after_evaluating_every_prefix(mock_netbox):
+    """The busiest prefixes should be returned even when they come last from NetBox."""
+    prefixes = [_prefix(i, f"10.0.{i}.0/24") for i in range(1, 6)]
+    mock_netbox.get.side_effect = _fake_get(prefixes, {"10.0.5.0/24": 200}, {})
+
+    result = netbox_get_prefix_utilization(within="10.0.0.0/16", limit=2)
+
+    assert [entry["id"] for entry in result] == [5, 1]
+
+
+def test_requires_prefix_ids_or_within():
+    with pytest.raises(ValueError):
+        netbox_get_prefix_utilization()
@@ -0,0 +1,35 @@
+"""Tests for the workflow prompts."""
+
+import re
+
+import pytest
+
+from netbox_mcp_server import server
+from netbox_mcp_server.netbox_types import NETBOX_OBJECT_TYPES
+from netbox_mcp_server.server import (
+    audit_site_primary_ips,
+    document_rack,
+    plan_device_decommission,
+)
+
+PROMPTS = [
+    (audit_site_primary_ips, "dc-east"),
+    (document_rack, "R101"),
+    (plan_device_decommission, "edge-rtr-01"),
+]
+
+
+@pytest.mark.parametrize(("prompt", "argument"), PROMPTS)
+def test_prompt_includes_argument(prompt, argument):
+    assert f'"{argument}"' in prompt(argument)
+
+
+@pytest.mark.parametrize(("prompt", "argument"), PROMPTS)
+def test_prompt_references_only_existing_tools_and_types(prompt, argument):
+    """Guards against prompts drifting from renamed tools or object types."""
+    text = prompt(argument)
+
+    for tool_name in re.findall(r"\bnetbox_\w+", text):
+        assert callable(getattr(server, tool_name, None)), tool_name
+    for object_type in re.findall(r'object_type "([a-z_.]+)"', text):
+        assert object_type in NETBOX_OBJECT_TYPES, object_type
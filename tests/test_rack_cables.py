@@ -0,0 +1,75 @@
+"""Tests for the rack cable audit tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_audit_rack_cables
+
+
+def _termination(object_id, name, device):
+    return {
+        "object_type": "dcim.interface",
+        "object_id": object_id,
+        "object": {"id": object_id, "display": name, "device": {"display": device}},
+    }
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_lists_cables_with_endpoints_and_attributes(mock_netbox):
+    """Cables should be filtered by rack and summarized with both endpoints."""
+    mock_netbox.get.return_value = {
+        "count": 1,
+        "next": None,
+        "previous": None,
+        "results": [
+            {
+                "id": 9,
+                "label": "A-9",
+                "type": "cat6",
+                "status": {"value": "connected", "label": "Connected"},
+                "color": "0000ff",
+                "length": 2.5,
+                "length_unit": {"value": "m", "label": "Meters"},
+                "a_terminations": [_termination(1, "eth0", "leaf1")],
+                "b_terminations": [_termination(2, "eth1", "spine1")],
+            }
+        ],
+    }
+
+    result = netbox_audit_rack_cables(rack_id=3)
+
+    assert mock_netbox.get.call_args[0][0] == "dcim/cables"
+    assert mock_netbox.get.call_args[1]["params"]["rack_id"] == 3
+    assert result["single_ended_count"] == 0
+    assert result["cables"] == [
+        {
+            "id": 9,
+            "label": "A-9",
+            "type": "cat6",
+            "status": "connected",
+            "color": "0000ff",
+            "length": 2.5,
+            "length_unit": "m",
+            "a_terminations": [{"id": 1, "display": "eth0", "parent": "leaf1"}],
+            "b_terminations": [{"id": 2, "display": "eth1", "parent": "spine1"}],
+            "single_ended": False,
+        }
+    ]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_flags_single_ended_cables(mock_netbox):
+    """Cables missing terminations on either side should be flagged and counted."""
+    mock_netbox.get.return_value = {
+        "count": 2,
+        "next": None,
+        "previous": None,
+        "results": [
+            {"id": 1, "a_terminations": [_termination(1, "eth0", "leaf1")], "b_terminations": []},
+            {"id": 2, "a_terminations": [], "b_terminations": [_termination(2, "eth1", "leaf2")]},
+        ],
+    }
+
+    result = netbox_audit_rack_cables(rack_id=3)
+
+    assert [cable["single_ended"] for cable in result["cables"]] == [True, True]
+    assert result["single_ended_count"] == 2
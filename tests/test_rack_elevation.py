@@ -0,0 +1,52 @@
+"""Tests for the rack elevation tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_get_rack_elevation
+from tests.conftest import paged
+
+UNITS = [
+    {"id": 2, "name": "U2", "device": None, "occupied": False},
+    {"id": 1, "name": "U1", "device": {"display": "sw1"}, "occupied": True},
+]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_returns_both_faces_by_default(mock_netbox):
+    """Without a face, both front and rear elevations should be returned."""
+    mock_netbox.get.return_value = paged(UNITS)
+
+    result = netbox_get_rack_elevation(rack_id=3)
+
+    assert set(result) == {"front", "rear"}
+    faces = [call[1]["params"]["face"] for call in mock_netbox.get.call_args_list]
+    assert faces == ["front", "rear"]
+    assert mock_netbox.get.call_args[0][0] == "dcim/racks/3/elevation"
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_units_are_summarized_in_order(mock_netbox):
+    """Units should keep NetBox's order and be reduced to unit/occupied/device."""
+    mock_netbox.get.return_value = paged(UNITS)
+
+    result = netbox_get_rack_elevation(rack_id=3, face="front")
+
+    assert result == {
+        "front": [
+            {"unit": "U2", "occupied": False, "device": None},
+            {"unit": "U1", "occupied": True, "device": "sw1"},
+        ]
+    }
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_include_svg_url(mock_netbox):
+    """SVG URLs should point at the elevation endpoint with render=svg."""
+    mock_netbox.get.return_value = paged([])
+    mock_netbox.api_url = "https://netbox.example.com/api"
+
+    result = netbox_get_rack_elevation(rack_id=3, face="rear", include_svg_url=True)
+
+    assert result["svg_urls"] == {
+        "rear": "https://netbox.example.com/api/dcim/racks/3/elevation/?face=rear&render=svg"
+    }
@@ -0,0 +1,63 @@
+"""Tests for the free rack space finder."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_find_rack_space
+from tests.conftest import fake_netbox_get, paged
+
+
+def _units(height, occupied):
+    return [{"id": u, "name": f"U{u}", "occupied": u in occupied} for u in range(height, 0, -1)]
+
+
+def _fake_get(racks, elevations, reservations=()):
+    def elevation(rack_id):
+        return lambda id, params: paged(elevations[(rack_id, params["face"])])
+
+    responses = {"dcim/racks": racks, "dcim/rack-reservations": list(reservations)}
+    for rack_id, _ in elevations:
+        responses[f"dcim/racks/{rack_id}/elevation"] = elevation(rack_id)
+    return fake_netbox_get(responses)
+
+
+RACKS = [{"id": 1, "display": "R1"}, {"id": 2, "display": "R2"}]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_finds_positions_free_on_both_faces(mock_netbox):
+    """Full-depth equipment needs the span free on front and rear; reservations count."""
+    elevations = {
+        # R1: U1-U3 and U7-U10 used on the front, U5 used on the rear
+        (1, "front"): _units(10, {1, 2, 3, 7, 8, 9, 10}),
+        (1, "rear"): _units(10, {1, 2, 3, 5, 7, 8, 9, 10}),
+        # R2: empty, but U4 is reserved
+        (2, "front"): _units(10, set()),
+        (2, "rear"): _units(10, set()),
+    }
+    mock_netbox.get.side_effect = _fake_get(RACKS, elevations, [{"rack": {"id": 2}, "units": [4]}])
+
+    result = netbox_find_rack_space(u_height=2, site_id=5)
+
+    assert result == [
+        {"rack_id": 2, "rack": "R2", "largest_free_span": 6, "positions": [1, 2, 5, 6, 7, 8, 9]}
+    ]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_half_depth_checks_one_face(mock_netbox):
+    elevations = {(1, "front"): _units(10, {1, 2, 3, 7, 8, 9, 10})}
+    mock_netbox.get.side_effect = _fake_get(RACKS[:1], elevations)
+
+    (rack,) = netbox_find_rack_space(u_height=3, rack_id=1, full_depth=False)
+
+    assert rack["positions"] == [4]
+    assert rack["largest_free_span"] == 3
+    rack_params = mock_netbox.get.call_args_list[0].kwargs["params"]
+    assert rack_params["id"] == 1
+
+
+def test_requires_exactly_one_scope():
+    with pytest.raises(ValueError):
+        netbox_find_rack_space(u_height=1)
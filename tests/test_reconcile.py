@@ -0,0 +1,107 @@
+"""Tests for the bulk result reconciliation tool."""
+
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_reconcile_objects
+
+
+def _fake_get(objects):
+    def get(endpoint, params=None, fallback_endpoint=None):
+        (field, value), *_ = ((k, v) for k, v in params.items() if k != "limit")
+        results = [obj for obj in objects if obj.get(field) == value]
+        return {"count": len(results), "next": None, "results": results}
+
+    return get
+
+
+SITES = [
+    {
+        "id": 1,
+        "name": "DC1",
+        "slug": "dc1",
+        "status": {"value": "active", "label": "Active"},
+        "region": {"id": 5, "name": "Europe", "slug": "europe"},
+        "tags": [{"id": 1, "name": "core", "slug": "core"}, {"id": 2, "name": "eu", "slug": "eu"}],
+        "custom_fields": {"code": "D1", "owner": "ops"},
+    },
+    {"id": 2, "name": "DC2", "slug": "dc2", "status": {"value": "planned"}},
+    {"id": 3, "name": "Dup", "slug": "dup-a"},
+    {"id": 4, "name": "Dup", "slug": "dup-b"},
+]
+
+
+def test_invalid_object_type_rejected():
+    """Unknown object types should be rejected before querying NetBox."""
+    with pytest.raises(ValueError, match="Invalid object_type"):
+        netbox_reconcile_objects(object_type="dcim.nonexistent", expected=[{"name": "x"}])
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_related_choice_list_and_custom_fields_match(mock_netbox):
+    """Related objects, choices, unordered tag lists and partial custom fields should match."""
+    mock_netbox.get.side_effect = _fake_get(SITES)
+
+    result = netbox_reconcile_objects(
+        object_type="dcim.site",
+        expected=[
+            {
+                "name": "DC1",
+                "status": "active",
+                "region": 5,
+                "tags": ["eu", "core"],
+                "custom_fields": {"code": "D1"},
+            }
+        ],
+    )
+
+    assert result["results"] == [{"index": 0, "status": "matched", "id": 1}]
+    assert mock_netbox.get.call_args[0][0] == "dcim/sites"
+    assert mock_netbox.get.call_args[1]["params"] == {"name": "DC1", "limit": 2}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_reports_mismatched_missing_and_ambiguous(mock_netbox):
+    """Each expected object should get a status, with differences for mismatches."""
+    mock_netbox.get.side_effect = _fake_get(SITES)
+
+    result = netbox_reconcile_objects(
+        object_type="dcim.site",
+        expected=[
+            {"name": "DC2", "status": "active"},
+            {"name": "DC9"},
+            {"name": "Dup"},
+        ],
+    )
+
+    mismatched, missing, ambiguous = result["results"]
+    assert mismatched["status"] == "mismatched"
+    assert mismatched["differences"] == {
+        "status": {"expected": "active", "actual": {"value": "planned"}}
+    }
+    assert missing == {"index": 1, "status": "missing", "id": None}
+    assert ambiguous["status"] == "ambiguous"
+    assert result["summary"] == {"matched": 0, "mismatched": 1, "missing": 1, "ambiguous": 1}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_id_takes_precedence_over_match_on(mock_netbox):
+    """Objects with an id should be looked up by it; others by match_on."""
+    mock_netbox.get.side_effect = _fake_get(SITES)
+
+    result = netbox_reconcile_objects(
+        object_type="dcim.site",
+        expected=[{"id": 3, "slug": "dup-a"}, {"slug": "dc2"}],
+        match_on="slug",
+    )
+
+    assert [r["status"] for r in result["results"]] == ["matched", "matched"]
+    lookups = [call[1]["params"] for call in mock_netbox.get.call_args_list]
+    assert lookups == [{"id": 3, "limit": 2}, {"slug": "dc2", "limit": 2}]
+
+
+def test_missing_lookup_field_rejected():
+    """An expected object without id or match_on field can't be looked up."""
+    with pytest.raises(ValueError, match="index 0 has neither 'id' nor 'name'"):
+        netbox_reconcile_objects(object_type="dcim.site", expected=[{"slug": "dc1"}])
@@ -0,0 +1,96 @@
+"""Tests for summarizing responses that exceed MAX_RESPONSE_CHARS."""
+
+from collections import OrderedDict
+from concurrent.futures import ThreadPoolExecutor
+from unittest.mock import patch
+
+import pytest
+from fastmcp.exceptions import NotFoundError
+
+from netbox_mcp_server.server import (
+    SCRATCHPAD_MAX_ENTRIES,
+    _store_scratchpad,
+    netbox_get_object_by_id,
+    netbox_get_objects,
+    scratchpad_result,
+)
+
+
+def _devices(n):
+    return [{"id": i, "name": f"device-{i}", "comments": "x" * 200} for i in range(n)]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_small_responses_untouched(mock_netbox):
+    page = {"count": 2, "next": None, "previous": None, "results": _devices(2)}
+    mock_netbox.get.return_value = page
+
+    with patch("netbox_mcp_server.server.max_response_chars", 100_000):
+        assert netbox_get_objects("dcim.device", {}) == page
+
+
+@patch("netbox_mcp_server.server.max_response_chars", 2000)
+@patch("netbox_mcp_server.server.netbox")
+def test_large_page_keeps_count_and_leading_results(mock_netbox):
+    mock_netbox.get.return_value = {"count": 500, "next": "p2", "results": _devices(50)}
+
+    result = netbox_get_objects("dcim.device", {}, limit=50)
+
+    assert result["count"] == 500
+    kept = result["truncated"]["results_returned"]
+    assert 0 < kept < 50
+    assert result["results"] == _devices(kept)
+    assert result["truncated"]["available_fields"] == ["comments", "id", "name"]
+    assert "fields=[...]" in result["truncated"]["guidance"]
+
+
+@patch("netbox_mcp_server.server.max_response_chars", 1000)
+@patch("netbox_mcp_server.server.netbox")
+def test_large_object_drops_biggest_fields(mock_netbox):
+    mock_netbox.get.return_value = {
+        "id": 7,
+        "name": "edge-01",
+        "config_context": {"ntp": ["10.0.0.1"] * 100},
+        "comments": "y" * 300,
+    }
+
+    result = netbox_get_object_by_id("dcim.device", 7)
+
+    assert result["id"] == 7
+    assert result["name"] == "edge-01"
+    assert result["truncated"]["omitted_fields"] == ["config_context"]
+    assert "comments" in result
+
+
+@patch("netbox_mcp_server.server.max_response_chars", 2000)
+@patch("netbox_mcp_server.server.netbox")
+def test_truncated_response_is_readable_as_resource(mock_netbox):
+    page = {"count": 50, "next": None, "results": _devices(50)}
+    mock_netbox.get.return_value = page
+
+    result = netbox_get_objects("dcim.device", {}, limit=50)
+
+    uri = result["truncated"]["resource_uri"]
+    assert uri.startswith("netbox://results/")
+    assert scratchpad_result(uri.removeprefix("netbox://results/")) == page
+
+
+def test_scratchpad_keeps_only_recent_results():
+    with patch("netbox_mcp_server.server._scratchpad", OrderedDict()):
+        uris = [_store_scratchpad({"n": n}) for n in range(SCRATCHPAD_MAX_ENTRIES + 1)]
+
+        with pytest.raises(NotFoundError):
+            scratchpad_result(uris[0].removeprefix("netbox://results/"))
+        assert scratchpad_result(uris[-1].removeprefix("netbox://results/")) == {
+            "n": SCRATCHPAD_MAX_ENTRIES
+        }
+
+
+def test_scratchpad_bounded_under_concurrent_stores():
+    """Tools store results from several worker threads at once."""
+    scratchpad = OrderedDict()
+    with patch("netbox_mcp_server.server._scratchpad", scratchpad):
+        with ThreadPoolExecutor(max_workers=8) as pool:
+            list(pool.map(_store_scratchpad, range(500)))
+
+    assert len(scratchpad) == SCRATCHPAD_MAX_ENTRIES
@@ -0,0 +1,75 @@
+"""Tests for the saved query library tools."""
+
+import json
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import (
+    load_saved_queries,
+    netbox_list_saved_queries,
+    netbox_run_saved_query,
+)
+
+QUERIES = {
+    "active-devices-in-site": {
+        "description": "Active devices in a site",
+        "object_type": "dcim.device",
+        "filters": {"site": "{site}", "status": "active", "role_id": ["{role_id}", 7]},
+        "fields": ["id", "name"],
+    },
+    "offline-devices": {
+        "object_type": "dcim.device",
+        "filters": {"status": "offline", "name__ic": "{prefix}-"},
+    },
+}
+
+
+@patch("netbox_mcp_server.server.saved_queries", QUERIES)
+def test_list_shows_parameters():
+    result = netbox_list_saved_queries()
+
+    assert [q["name"] for q in result] == ["active-devices-in-site", "offline-devices"]
+    assert result[0]["parameters"] == ["site", "role_id"]
+    assert result[1]["description"] == ""
+
+
+@patch("netbox_mcp_server.server.saved_queries", QUERIES)
+@patch("netbox_mcp_server.server.netbox")
+def test_run_fills_placeholders_keeping_types(mock_netbox):
+    """A lone placeholder keeps the parameter's type; embedded ones are formatted."""
+    mock_netbox.get.return_value = {"count": 0, "results": []}
+
+    netbox_run_saved_query(
+        "active-devices-in-site", parameters={"site": "dc-east", "role_id": 3}, limit=10
+    )
+    netbox_run_saved_query("offline-devices", parameters={"prefix": "edge"})
+
+    first, second = (call[1]["params"] for call in mock_netbox.get.call_args_list)
+    assert first["site"] == "dc-east"
+    assert first["role_id"] == [3, 7]
+    assert first["fields"] == "id,name"
+    assert first["limit"] == 10
+    assert second["name__ic"] == "edge-"
+
+
+@patch("netbox_mcp_server.server.saved_queries", QUERIES)
+def test_run_rejects_unknown_query_and_missing_parameters():
+    with pytest.raises(ValueError, match="Available: active-devices-in-site, offline-devices"):
+        netbox_run_saved_query("nope")
+    with pytest.raises(ValueError, match="requires parameters: role_id"):
+        netbox_run_saved_query("active-devices-in-site", parameters={"site": "dc-east"})
+
+
+def test_load_validates_queries(tmp_path):
+    path = tmp_path / "queries.json"
+    path.write_text(json.dumps(QUERIES))
+    assert load_saved_queries(str(path)) == QUERIES
+
+    path.write_text(json.dumps({"bad": {"object_type": "dcim.nothing"}}))
+    with pytest.raises(ValueError, match="valid object_type"):
+        load_saved_queries(str(path))
+
+    path.write_text(json.dumps({"bad": {"object_type": "dcim.device", "filters": {"id__in": 1}}}))
+    with pytest.raises(ValueError, match="Saved query 'bad'"):
+        load_saved_queries(str(path))
@@ -0,0 +1,105 @@
+"""Tests for recent request statistics and the server health tool."""
+
+import threading
+import time
+from unittest.mock import MagicMock, patch
+
+import httpx
+import pytest
+
+from netbox_mcp_server.netbox_client import NetBoxRestClient
+from netbox_mcp_server.server import netbox_get_server_health
+
+
+def _response(status):
+    response = MagicMock()
+    response.status_code = status
+    return response
+
+
+def test_request_stats_counts_integration_errors_and_percentiles():
+    client = NetBoxRestClient(url="https://netbox.example.com", token="t", max_retries=0)
+    client.session = MagicMock()
+    client.session.get.side_effect = [
+        _response(200),
+        _response(404),
+        _response(503),
+        httpx.ConnectError("refused"),
+    ]
+
+    for _ in range(3):
+        client._get_with_retry("https://netbox.example.com/api/dcim/sites/", None)
+    with pytest.raises(httpx.ConnectError):
+        client._get_with_retry("https://netbox.example.com/api/dcim/sites/", None)
+
+    stats = client.request_stats()
+    assert stats["requests"] == 4
+    assert stats["errors"] == 2  # 503 and the connection failure; 404 is an answer
+    assert stats["error_rate"] == 0.5
+    assert stats["latency_ms"]["p50"] is not None
+
+
+def test_request_stats_respects_window():
+    client = NetBoxRestClient(url="https://netbox.example.com", token="t")
+    client._recent_requests.append((time.monotonic() - 600, 0.1, 500))
+
+    stats = client.request_stats(window=300)
+
+    assert stats == {
+        "requests": 0,
+        "errors": 0,
+        "error_rate": 0.0,
+        "latency_ms": {"p50": None, "p95": None, "p99": None},
+    }
+
+
+def test_request_stats_safe_while_requests_are_recorded():
+    """Reading stats while other threads record round trips should not fail."""
+    client = NetBoxRestClient(url="https://netbox.example.com", token="t")
+    stop = threading.Event()
+
+    def record():
+        while not stop.is_set():
+            client._record_request(time.monotonic(), 200)
+
+    writer = threading.Thread(target=record)
+    writer.start()
+    try:
+        for _ in range(2000):
+            client.request_stats()
+    finally:
+        stop.set()
+        writer.join()
+
+    assert client.request_stats()["requests"] > 0
+
+
+def _stats(requests, error_rate, p95):
+    return {
+        "requests": requests,
+        "errors": 0,
+        "error_rate": error_rate,
+        "latency_ms": {"p50": p95, "p95": p95, "p99": p95},
+    }
+
+
+@pytest.mark.parametrize(
+    ("stats", "expected"),
+    [
+        (_stats(0, 0.0, None), "idle"),
+        (_stats(20, 0.0, 120.0), "healthy"),
+        (_stats(20, 0.25, 120.0), "degraded"),
+        (_stats(20, 0.0, 8000.0), "degraded"),
+    ],
+)
+@patch("netbox_mcp_server.server.netbox")
+def test_health_status_verdict(mock_netbox, stats, expected):
+    mock_netbox.request_stats.return_value = stats
+    mock_netbox.max_retries = 3
+    mock_netbox.retry_backoff = 0.5
+
+    result = netbox_get_server_health(window_minutes=10)
+
+    assert result["status"] == expected
+    mock_netbox.request_stats.assert_called_once_with(600)
+    assert result["retry_policy"] == {"max_retries": 3, "retry_backoff_seconds": 0.5}
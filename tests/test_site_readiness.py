@@ -0,0 +1,61 @@
+"""Tests for the site readiness checklist tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_check_site_readiness
+
+
+def _fake_get(counts):
+    def get(endpoint, id=None, params=None, fallback_endpoint=None):
+        if id is not None:
+            return {"id": id, "display": "DC1"}
+        return {"count": counts.get(endpoint, 0), "next": None, "results": []}
+
+    return get
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_fully_modeled_site_is_ready(mock_netbox):
+    """A site with every object kind present should pass every check."""
+    mock_netbox.get.side_effect = _fake_get(
+        {
+            "dcim/locations": 2,
+            "dcim/racks": 10,
+            "ipam/prefixes": 4,
+            "ipam/vlans": 1,
+            "tenancy/contact-assignments": 1,
+            "circuits/circuits": 2,
+        }
+    )
+
+    result = netbox_check_site_readiness(site_id=1)
+
+    assert result["site"] == "DC1"
+    assert result["ready"] is True
+    assert all(check["passed"] for check in result["checks"])
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_missing_objects_fail_their_checks(mock_netbox):
+    """Checks with no matching objects should fail and mark the site not ready."""
+    mock_netbox.get.side_effect = _fake_get({"dcim/racks": 3, "ipam/prefixes": 1})
+
+    result = netbox_check_site_readiness(site_id=1)
+
+    assert result["ready"] is False
+    failed = [check["check"] for check in result["checks"] if not check["passed"]]
+    assert failed == ["has_locations", "has_management_vlan", "has_contacts", "has_circuits"]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_management_vlan_role_and_contact_filters(mock_netbox):
+    """VLANs should be filtered by role slug and contacts by the site object."""
+    mock_netbox.get.side_effect = _fake_get({})
+
+    netbox_check_site_readiness(site_id=7, management_vlan_role="oob")
+
+    calls = mock_netbox.get.call_args_list
+    params_by_endpoint = {call[0][0]: call[1].get("params") for call in calls}
+    assert params_by_endpoint["ipam/vlans"]["role"] == "oob"
+    assert params_by_endpoint["tenancy/contact-assignments"]["object_type"] == "dcim.site"
+    assert params_by_endpoint["tenancy/contact-assignments"]["object_id"] == 7
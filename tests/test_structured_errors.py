@@ -0,0 +1,82 @@
+"""Tests for machine-readable tool error objects."""
+
+import asyncio
+import json
+from unittest.mock import MagicMock
+
+import httpx
+import pytest
+from fastmcp.exceptions import ToolError
+
+from netbox_mcp_server.server import StructuredErrorMiddleware, classify_error
+
+
+def _status_error(status_code: int) -> httpx.HTTPStatusError:
+    response = MagicMock()
+    response.status_code = status_code
+    return httpx.HTTPStatusError("error", request=MagicMock(), response=response)
+
+
+@pytest.mark.parametrize(
+    ("status_code", "category", "retryable"),
+    [
+        (400, "validation", False),
+        (401, "auth", False),
+        (403, "auth", False),
+        (404, "not_found", False),
+        (429, "rate_limit", True),
+        (500, "server", True),
+        (503, "server", True),
+    ],
+)
+def test_http_status_errors_are_classified(status_code, category, retryable):
+    """NetBox HTTP status codes should map to the expected category and retry flag."""
+    error = classify_error(_status_error(status_code))
+
+    assert error["category"] == category
+    assert error["retryable"] is retryable
+    assert error["suggested_action"]
+
+
+def test_timeout_is_retryable_server_error():
+    """A timeout talking to NetBox should be reported as a retryable server error."""
+    error = classify_error(httpx.ReadTimeout("timed out"))
+
+    assert error["category"] == "server"
+    assert error["retryable"] is True
+
+
+def test_value_error_is_validation_error():
+    """Argument validation failures should keep their message."""
+    error = classify_error(ValueError("Invalid object_type"))
+
+    assert error["category"] == "validation"
+    assert error["retryable"] is False
+    assert error["message"] == "Invalid object_type"
+
+
+def test_middleware_reraises_as_tool_error_with_json():
+    """The middleware should unwrap FastMCP's ToolError and attach the error object."""
+
+    async def call_next(context):
+        try:
+            raise _status_error(404)
+        except httpx.HTTPStatusError as e:
+            raise ToolError("Error calling tool") from e
+
+    with pytest.raises(ToolError) as exc_info:
+        asyncio.run(StructuredErrorMiddleware().on_call_tool(MagicMock(), call_next))
+
+    payload = json.loads(str(exc_info.value))
+    assert payload["error"]["category"] == "not_found"
+
+
+def test_middleware_passes_through_results():
+    """Successful tool calls should be returned untouched."""
+
+    async def call_next(context):
+        return {"ok": True}
+
+    result = asyncio.run(StructuredErrorMiddleware().on_call_tool(MagicMock(), call_next))
+
+    assert result == {"ok": True}
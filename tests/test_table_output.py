@@ -0,0 +1,149 @@
+"""Tests for netbox_get_objects CSV and markdown table output."""
+
+import json
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import netbox_get_objects
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_csv_replaces_results_and_keeps_pagination(mock_netbox):
+    """CSV output should keep count/next and flatten nested values."""
+    mock_netbox.get.return_value = {
+        "count": 30,
+        "next": "https://netbox.example.com/api/dcim/devices/?offset=2",
+        "previous": None,
+        "results": [
+            {
+                "name": "sw-01",
+                "id": 1,
+                "status": {"value": "active", "label": "Active"},
+                "site": {"id": 3, "display": "DC1", "name": "DC1"},
+                "tags": [{"name": "core"}, {"name": "edge"}],
+                "serial": None,
+            },
+            {
+                "name": "sw-02, spare",
+                "id": 2,
+                "status": {"value": "offline", "label": "Offline"},
+                "site": {"id": 3, "display": "DC1", "name": "DC1"},
+                "tags": [],
+                "serial": "ABC",
+            },
+        ],
+    }
+
+    result = netbox_get_objects(
+        "dcim.device",
+        {},
+        fields=["id", "name", "status", "site", "tags", "serial"],
+        limit=2,
+        output_format="csv",
+    )
+
+    assert "results" not in result
+    assert result["count"] == 30
+    assert result["next"].endswith("offset=2")
+    assert result["csv"] == (
+        "id,name,status,site,tags,serial\n"
+        "1,sw-01,active,DC1,core; edge,\n"
+        '2,"sw-02, spare",offline,DC1,,ABC\n'
+    )
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_csv_without_fields_uses_result_keys(mock_netbox):
+    """Without fields, columns follow the keys of the returned objects."""
+    mock_netbox.get.return_value = {
+        "count": 1,
+        "next": None,
+        "previous": None,
+        "results": [{"id": 1, "custom_fields": {"owner": "neteng"}}],
+    }
+
+    result = netbox_get_objects("dcim.site", {}, output_format="csv")
+
+    assert result["csv"] == 'id,custom_fields\n1,"{""owner"": ""neteng""}"\n'
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_json_is_default(mock_netbox):
+    page = {"count": 0, "next": None, "previous": None, "results": []}
+    mock_netbox.get.return_value = page
+
+    assert netbox_get_objects("dcim.site", {}) == page
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_markdown_table_escapes_cells(mock_netbox):
+    """Markdown output should be a pipe table with pipes and newlines escaped in cells."""
+    mock_netbox.get.return_value = {
+        "count": 1,
+        "next": None,
+        "previous": None,
+        "results": [
+            {
+                "id": 1,
+                "name": "sw-01",
+                "status": {"value": "active", "label": "Active"},
+                "comments": "uplink | spare\nsee ticket",
+            }
+        ],
+    }
+
+    result = netbox_get_objects(
+        "dcim.device", {}, fields=["name", "status", "comments"], output_format="markdown"
+    )
+
+    assert "results" not in result
+    assert result["markdown"] == (
+        "| name | status | comments | id |\n"
+        "| --- | --- | --- | --- |\n"
+        "| sw-01 | active | uplink \\| spare see ticket | 1 |\n"
+    )
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_markdown_empty_results(mock_netbox):
+    mock_netbox.get.return_value = {"count": 0, "next": None, "previous": None, "results": []}
+
+    assert netbox_get_objects("dcim.device", {}, output_format="markdown")["markdown"] == ""
+
+
+def _devices(n):
+    site = {"id": 3, "url": "https://netbox.example.com/api/dcim/sites/3/", "display": "DC1"}
+    return [{"id": i, "name": f"sw-{i:02}", "site": site} for i in range(n)]
+
+
+@pytest.mark.parametrize("output_format", ["csv", "markdown"])
+@patch("netbox_mcp_server.server.max_response_chars", 1200)
+@patch("netbox_mcp_server.server.netbox")
+def test_table_that_fits_is_not_truncated_by_json_size(mock_netbox, output_format):
+    """The size limit applies to the table returned, which is far smaller than the JSON."""
+    devices = _devices(20)
+    assert len(json.dumps(devices)) > 1200
+    mock_netbox.get.return_value = {"count": 20, "next": None, "results": devices}
+
+    result = netbox_get_objects("dcim.device", {}, limit=20, output_format=output_format)
+
+    assert "truncated" not in result
+    assert "sw-19" in result[output_format]
+
+
+@pytest.mark.parametrize("output_format", ["csv", "markdown"])
+@patch("netbox_mcp_server.server.max_response_chars", 800)
+@patch("netbox_mcp_server.server.netbox")
+def test_oversized_table_is_truncated_by_rows(mock_netbox, output_format):
+    """Rows are dropped until the table response fits, and the counts describe the table."""
+    mock_netbox.get.return_value = {"count": 100, "next": None, "results": _devices(100)}
+
+    result = netbox_get_objects("dcim.device", {}, limit=100, output_format=output_format)
+
+    kept = result["truncated"]["results_returned"]
+    assert 0 < kept < 100
+    assert "results" not in result
+    assert f"sw-{kept - 1:02}" in result[output_format]
+    assert f"sw-{kept:02}" not in result[output_format]
+    assert len(json.dumps(result)) <= 800
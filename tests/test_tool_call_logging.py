@@ -0,0 +1,108 @@
+"""Tests for per-tool-call structured logging."""
+
+import asyncio
+import json
+import logging
+from unittest.mock import MagicMock
+
+import pytest
+from fastmcp.exceptions import ToolError
+
+from netbox_mcp_server.config import JsonFormatter, request_id_var
+from netbox_mcp_server.server import ToolCallLoggingMiddleware
+
+
+class _Capture(logging.Handler):
+    def __init__(self):
+        super().__init__()
+        self.records = []
+
+    def emit(self, record):
+        record.request_id = request_id_var.get()
+        self.records.append(record)
+
+
+@pytest.fixture
+def captured():
+    """Capture records emitted by the server logger."""
+    handler = _Capture()
+    logger = logging.getLogger("netbox_mcp_server.server")
+    logger.addHandler(handler)
+    logger.setLevel(logging.INFO)
+    yield handler.records
+    logger.removeHandler(handler)
+
+
+def _context(name, arguments):
+    context = MagicMock()
+    context.message.name = name
+    context.message.arguments = arguments
+    return context
+
+
+def test_successful_call_logged_with_fields(captured):
+    """A completed call should log tool, object_type, status and duration."""
+
+    async def call_next(context):
+        return {"ok": True}
+
+    context = _context("netbox_get_objects", {"object_type": "dcim.site"})
+    result = asyncio.run(ToolCallLoggingMiddleware().on_call_tool(context, call_next))
+
+    assert result == {"ok": True}
+    (record,) = captured
+    assert record.tool == "netbox_get_objects"
+    assert record.object_type == "dcim.site"
+    assert record.status == "ok"
+    assert record.duration_ms >= 0
+    assert record.request_id
+
+
+def test_failed_call_logged_with_error_category(captured):
+    """A failed call should be logged as an error with its category and re-raised."""
+
+    async def call_next(context):
+        try:
+            raise ValueError("Invalid object_type")
+        except ValueError as e:
+            raise ToolError("Error calling tool") from e
+
+    context = _context("netbox_get_objects", {})
+    with pytest.raises(ToolError):
+        asyncio.run(ToolCallLoggingMiddleware().on_call_tool(context, call_next))
+
+    (record,) = captured
+    assert record.levelno == logging.WARNING
+    assert record.status == "error"
+    assert record.error_category == "validation"
+
+
+def test_request_id_reset_after_call(captured):
+    """The request ID should only be set while the tool call runs."""
+
+    async def call_next(context):
+        return None
+
+    asyncio.run(ToolCallLoggingMiddleware().on_call_tool(_context("t", {}), call_next))
+
+    assert request_id_var.get() is None
+
+
+def test_json_formatter_includes_structured_fields():
+    """JSON log lines should carry the message, level and any structured extras."""
+    record = logging.LogRecord("netbox", logging.INFO, __file__, 1, "Tool done", None, None)
+    record.tool = "netbox_get_objects"
+    record.duration_ms = 12.5
+    token = request_id_var.set("abc123")
+    try:
+        line = JsonFormatter().format(record)
+    finally:
+        request_id_var.reset(token)
+
+    entry = json.loads(line)
+    assert entry["message"] == "Tool done"
+    assert entry["level"] == "INFO"
+    assert entry["request_id"] == "abc123"
+    assert entry["tool"] == "netbox_get_objects"
+    assert entry["duration_ms"] == 12.5
+    assert "object_type" not in entry
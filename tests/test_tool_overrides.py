@@ -0,0 +1,73 @@
+"""Tests for operator-defined tool description overrides."""
+
+import asyncio
+import json
+from types import SimpleNamespace
+from unittest.mock import AsyncMock, patch
+
+import pytest
+
+from netbox_mcp_server.server import _apply_tool_overrides, load_tool_overrides, mcp
+
+
+def _tool():
+    return SimpleNamespace(
+        description="Get objects from NetBox",
+        parameters={
+            "properties": {
+                "filters": {"type": "object", "description": "Filters to apply"},
+                "limit": {"type": "integer"},
+            }
+        },
+    )
+
+
+def test_append_and_parameter_hints():
+    tool = _tool()
+    overrides = {
+        "netbox_get_objects": {
+            "append": "House rule: always filter by tenant_id.",
+            "parameters": {"filters": "Include tenant_id.", "limit": "Keep it under 20."},
+        }
+    }
+
+    with patch.object(mcp, "get_tool", AsyncMock(return_value=tool)):
+        asyncio.run(_apply_tool_overrides(overrides))
+
+    assert tool.description == "Get objects from NetBox\n\nHouse rule: always filter by tenant_id."
+    properties = tool.parameters["properties"]
+    assert properties["filters"]["description"] == "Filters to apply\n\nInclude tenant_id."
+    assert properties["limit"]["description"] == "Keep it under 20."
+
+
+def test_description_replaced_before_append():
+    tool = _tool()
+    overrides = {"netbox_get_objects": {"description": "Lookup tool.", "append": "Be brief."}}
+
+    with patch.object(mcp, "get_tool", AsyncMock(return_value=tool)):
+        asyncio.run(_apply_tool_overrides(overrides))
+
+    assert tool.description == "Lookup tool.\n\nBe brief."
+
+
+def test_unknown_tool_and_parameter_rejected():
+    with (
+        patch.object(mcp, "get_tool", AsyncMock(return_value=None)),
+        pytest.raises(ValueError, match="Unknown tool 'netbox_get_objcts'"),
+    ):
+        asyncio.run(_apply_tool_overrides({"netbox_get_objcts": {"append": "x"}}))
+
+    overrides = {"netbox_get_objects": {"parameters": {"tenant": "x"}}}
+    with (
+        patch.object(mcp, "get_tool", AsyncMock(return_value=_tool())),
+        pytest.raises(ValueError, match="no parameter 'tenant'"),
+    ):
+        asyncio.run(_apply_tool_overrides(overrides))
+
+
+def test_load_rejects_unknown_keys(tmp_path):
+    path = tmp_path / "overrides.json"
+    path.write_text(json.dumps({"netbox_get_objects": {"prepend": "x"}}))
+
+    with pytest.raises(ValueError, match="Unknown override keys for 'netbox_get_objects': prepend"):
+        load_tool_overrides(str(path))
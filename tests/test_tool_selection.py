@@ -0,0 +1,39 @@
+"""Tests for ENABLED_TOOLS / DISABLED_TOOLS tool selection."""
+
+import asyncio
+from unittest.mock import patch
+
+import pytest
+
+from netbox_mcp_server.server import _apply_tool_selection, mcp
+
+
+def test_allowlist_enables_only_named_tools():
+    """An allowlist should be applied as an exclusive enable."""
+    with patch.object(mcp, "enable") as mock_enable, patch.object(mcp, "disable") as mock_disable:
+        asyncio.run(_apply_tool_selection(["netbox_get_objects"], []))
+
+    mock_enable.assert_called_once_with(names={"netbox_get_objects"}, only=True)
+    mock_disable.assert_not_called()
+
+
+def test_denylist_disables_named_tools():
+    """A denylist should disable only the named tools."""
+    with patch.object(mcp, "enable") as mock_enable, patch.object(mcp, "disable") as mock_disable:
+        asyncio.run(_apply_tool_selection([], ["netbox_get_changelogs"]))
+
+    mock_enable.assert_not_called()
+    mock_disable.assert_called_once_with(names={"netbox_get_changelogs"})
+
+
+def test_unknown_tool_name_rejected():
+    """A typo in either list should fail instead of being silently ignored."""
+    with (
+        patch.object(mcp, "enable") as mock_enable,
+        patch.object(mcp, "disable") as mock_disable,
+        pytest.raises(ValueError, match="Unknown tool 'netbox_get_objcts'"),
+    ):
+        asyncio.run(_apply_tool_selection([], ["netbox_get_objcts"]))
+
+    mock_enable.assert_not_called()
+    mock_disable.assert_not_called()
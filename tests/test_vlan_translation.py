@@ -0,0 +1,73 @@
+"""Tests for the VLAN translation policy report tool."""
+
+from unittest.mock import patch
+
+from netbox_mcp_server.server import netbox_get_vlan_translation_policies
+from tests.conftest import fake_netbox_get, paged
+
+
+RESPONSES = {
+    "ipam/vlan-translation-policies": [
+        {"id": 1, "name": "Customer A", "description": ""},
+        {"id": 2, "name": "Customer B", "description": "Unused"},
+    ],
+    "ipam/vlan-translation-rules": [
+        {"policy": {"id": 1}, "local_vid": 200, "remote_vid": 20, "description": ""},
+        {"policy": {"id": 1}, "local_vid": 100, "remote_vid": 10, "description": "mgmt"},
+    ],
+    "dcim/interfaces": [
+        {
+            "id": 5,
+            "display": "xe-0/0/1",
+            "device": {"display": "pe1"},
+            "vlan_translation_policy": {"id": 1},
+        }
+    ],
+    "virtualization/interfaces": [
+        {
+            "id": 8,
+            "display": "eth0",
+            "virtual_machine": {"display": "vrouter1"},
+            "vlan_translation_policy": {"id": 1},
+        }
+    ],
+}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_resolves_rules_and_interfaces_per_policy(mock_netbox):
+    """Rules and interfaces should be grouped under their policy, rules ordered by VID."""
+    mock_netbox.get.side_effect = fake_netbox_get(RESPONSES)
+
+    result = netbox_get_vlan_translation_policies()
+
+    customer_a, customer_b = result
+    assert [rule["local_vid"] for rule in customer_a["rules"]] == [100, 200]
+    assert customer_a["interfaces"] == [
+        {"id": 5, "display": "xe-0/0/1", "parent": "pe1"},
+        {"id": 8, "display": "eth0", "parent": "vrouter1"},
+    ]
+    assert customer_b["rules"] == []
+    assert customer_b["interfaces"] == []
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_single_policy_scopes_related_queries(mock_netbox):
+    """With policy_id, rules and interfaces should be filtered to that policy."""
+    mock_netbox.get.side_effect = fake_netbox_get(RESPONSES)
+
+    result = netbox_get_vlan_translation_policies(policy_id=2)
+
+    assert [policy["id"] for policy in result] == [2]
+    for call in mock_netbox.get.call_args_list[1:]:
+        params = call[1]["params"]
+        assert params.get("policy_id", params.get("vlan_translation_policy_id")) == [2]
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_no_policies_returns_empty_list(mock_netbox):
+    """With no policies defined, no further queries should be made."""
+    mock_netbox.get.side_effect = fake_netbox_get({}, missing_ok=True)
+
+    assert netbox_get_vlan_translation_policies() == []
+    assert mock_netbox.get.call_count == 1
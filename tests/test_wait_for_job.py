@@ -0,0 +1,95 @@
+"""Tests for the background job wait tool."""
+
+import asyncio
+from unittest.mock import MagicMock, patch
+
+from netbox_mcp_server.netbox_client import NetBoxRestClient
+from netbox_mcp_server.server import netbox_wait_for_job
+
+
+class FakeClock:
+    """Stands in for time.monotonic and asyncio.sleep so waits take no real time."""
+
+    def __init__(self):
+        self.now = 0.0
+        self.sleeps = []
+
+    def monotonic(self):
+        return self.now
+
+    async def sleep(self, seconds):
+        self.sleeps.append(seconds)
+        self.now += seconds
+
+
+def _wait(clock, **kwargs):
+    with (
+        patch("netbox_mcp_server.server.time.monotonic", clock.monotonic),
+        patch("netbox_mcp_server.server.asyncio.sleep", clock.sleep),
+    ):
+        return asyncio.run(netbox_wait_for_job(**kwargs))
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_polls_until_job_finishes(mock_netbox):
+    """The tool should keep polling fresh job data until a finished status is seen."""
+    mock_netbox.get.side_effect = [
+        {"id": 9, "status": {"value": "pending"}},
+        {"id": 9, "status": {"value": "running"}},
+        {"id": 9, "status": {"value": "completed"}, "data": {"output": "done"}},
+    ]
+    clock = FakeClock()
+
+    result = _wait(clock, job_id=9, timeout_seconds=60, poll_interval_seconds=2)
+
+    mock_netbox.get.assert_called_with("core/jobs", id=9, use_cache=False)
+    assert mock_netbox.get.call_count == 3
+    assert clock.sleeps == [2, 2]
+    assert result["status"] == "completed"
+    assert result["finished"] is True
+    assert result["waited_seconds"] == 4
+    assert result["job"]["data"] == {"output": "done"}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_errored_job_is_finished(mock_netbox):
+    """Errored jobs are final and should be returned immediately."""
+    mock_netbox.get.return_value = {"id": 9, "status": {"value": "errored"}, "error": "boom"}
+
+    result = _wait(FakeClock(), job_id=9)
+
+    assert result["finished"] is True
+    assert result["job"]["error"] == "boom"
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_gives_up_after_timeout(mock_netbox):
+    """A job still running at the deadline should be reported as unfinished."""
+    mock_netbox.get.return_value = {"id": 9, "status": {"value": "running"}}
+    clock = FakeClock()
+
+    result = _wait(clock, job_id=9, timeout_seconds=5, poll_interval_seconds=2)
+
+    assert clock.sleeps == [2, 2, 1]
+    assert result["status"] == "running"
+    assert result["finished"] is False
+    assert result["waited_seconds"] == 5
+
+
+def test_use_cache_false_fetches_and_refreshes():
+    """use_cache=False should skip the cache lookup but still refresh the cached entry."""
+    client = NetBoxRestClient(url="https://netbox.example.com", token="test-token", cache_ttl=30)
+    responses = []
+    for status in ("running", "completed"):
+        response = MagicMock()
+        response.status_code = 200
+        response.json.return_value = {"id": 1, "status": status}
+        responses.append(response)
+
+    with patch.object(client.session, "get", side_effect=responses) as mock_get:
+        client.get("core/jobs", id=1)
+        fresh = client.get("core/jobs", id=1, use_cache=False)
+        cached = client.get("core/jobs", id=1)
+
+    assert mock_get.call_count == 2
+    assert fresh == cached == {"id": 1, "status": "completed"}
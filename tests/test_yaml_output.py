@@ -0,0 +1,56 @@
+"""Tests for YAML output from the read tools."""
+
+from unittest.mock import patch
+
+import yaml
+
+from netbox_mcp_server.server import (
+    netbox_get_changelogs,
+    netbox_get_object_by_id,
+    netbox_get_objects,
+)
+
+PAGE = {
+    "count": 1,
+    "next": None,
+    "previous": None,
+    "results": [{"id": 1, "name": "sw-01", "site": {"id": 3, "display": "DC1"}}],
+}
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_get_objects_yaml_round_trips(mock_netbox):
+    """YAML output should carry the full paginated response in block style."""
+    mock_netbox.get.return_value = PAGE
+
+    result = netbox_get_objects("dcim.device", {}, output_format="yaml")
+
+    assert isinstance(result, str)
+    assert result.startswith("count: 1\n")
+    assert "{" not in result
+    assert yaml.safe_load(result) == PAGE
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_get_object_by_id_yaml(mock_netbox):
+    mock_netbox.get.return_value = {"id": 1, "name": "sw-01", "comments": "Ünïcode"}
+
+    result = netbox_get_object_by_id("dcim.device", 1, output_format="yaml")
+
+    assert result == "id: 1\nname: sw-01\ncomments: Ünïcode\n"
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_get_changelogs_yaml(mock_netbox):
+    mock_netbox.get.return_value = {"count": 0, "next": None, "previous": None, "results": []}
+
+    result = netbox_get_changelogs({}, output_format="yaml")
+
+    assert yaml.safe_load(result)["results"] == []
+
+
+@patch("netbox_mcp_server.server.netbox")
+def test_json_output_unchanged(mock_netbox):
+    mock_netbox.get.return_value = PAGE
+
+    assert netbox_get_objects("dcim.device", {}) == PAGE